@@ -16,12 +16,14 @@ type options struct {
 
 type fetchReleasesCmd struct {
 	Exclude []string `kong:"default='go1.7.2',help='Go versions to exclude. go1.7.2 is a default because it was retracted.'"`
+	BaseURL string   `kong:"name=base-url,help='base url of a go.dev mirror like https://golang.google.cn. file urls in the output point at the mirror.'"`
 }
 
 func (x *fetchReleasesCmd) Run(k *kong.Context) error {
 	ctx := context.Background()
 	releases, err := goreleases.FetchReleases(ctx, &goreleases.FetchReleasesOptions{
 		SkipVersions: x.Exclude,
+		BaseURL:      x.BaseURL,
 	})
 	if err != nil {
 		return fmt.Errorf("couldn't build releases %v", err)
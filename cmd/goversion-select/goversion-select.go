@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	"github.com/alecthomas/kong"
+	"github.com/willabides/goversions/goreleases"
 	"github.com/willabides/goversions/goversion"
 )
 
@@ -19,20 +20,31 @@ For example, get the newest version of go 1.15 like so:
 
   curl -s 'https://raw.githubusercontent.com/WillAbides/goreleases/main/versions.txt' \
     | goversion-select -i -c '1.15' -
+
+Or get the newest version that satisfies a module's go directive:
+
+  curl -s 'https://raw.githubusercontent.com/WillAbides/goreleases/main/versions.txt' \
+    | goversion-select -i --gomod go.mod -
 `
 
 var version = "unknown"
 
 var cli struct {
 	Version            kong.VersionFlag `kong:"short=v,help='output goversion-select version and exit'"`
-	Constraint         string           `kong:"required,short=c,help='constraint to match'"`
+	Constraint         string           `kong:"short=c,help='constraint to match. may also be one of the aliases stable, oldstable, latest or supported'"`
+	Gomod              string           `kong:"type=existingfile,help='match versions that satisfy the go directive in this go.mod or go.work file instead of a constraint. implies --gover'"`
 	MaxResults         int              `kong:"short=n,help='maximum number of results to output'"`
 	IgnoreInvalid      bool             `kong:"short=i,help='ignore invalid candidates instead of erroring'"`
-	ValidateConstraint bool             `kong:"help='just validate the constraint. exits non-zero if invalid'"`
-	Candidates         []string         `kong:"arg,help='candidate versions to consider -- value of \"-\" indicates stdin'"`
+	Lenient            bool             `kong:"help='accept candidates written like v1.21.0, Go 1.21 or 1.21.0-rc.1'"`
+	ValidateConstraint bool             `kong:"help='just validate the constraint and output its normalized and semver forms. exits non-zero if invalid'"`
+	Explain            bool             `kong:"help='explain why each candidate does or does not match instead of selecting versions'"`
+	Gover              bool             `kong:"help='order versions the way the go command does (go1.21 < go1.21rc1 < go1.21.0)'"`
+	Prereleases        string           `kong:"enum='default,exclude,include,only,fallback',default='default',help='which prereleases to match. default only matches prereleases named in the constraint. fallback includes prereleases when no release matches'"`
+	Embedded           bool             `kong:"help='add the go releases embedded in goversion-select to the candidates. they are only as new as the snapshot time in --version'"`
+	Candidates         []string         `kong:"arg,optional,help='candidate versions to consider -- value of \"-\" indicates stdin'"`
 }
 
-func getVersions(args []string, stdin io.Reader, ignore bool) ([]*goversion.Version, error) {
+func getVersions(args []string, stdin io.Reader, ignore bool, options *goversion.ParseOptions) ([]*goversion.Version, error) {
 	res := make([]*goversion.Version, 0, len(args))
 	doStdin := false
 	var err error
@@ -41,7 +53,7 @@ func getVersions(args []string, stdin io.Reader, ignore bool) ([]*goversion.Vers
 			doStdin = true
 			break
 		}
-		res, err = addVersion(arg, ignore, res)
+		res, err = addVersion(arg, ignore, options, res)
 		if err != nil {
 			return nil, err
 		}
@@ -51,7 +63,7 @@ func getVersions(args []string, stdin io.Reader, ignore bool) ([]*goversion.Vers
 	}
 	r := bufio.NewScanner(stdin)
 	for r.Scan() {
-		res, err = addVersion(r.Text(), ignore, res)
+		res, err = addVersion(r.Text(), ignore, options, res)
 		if err != nil {
 			return nil, err
 		}
@@ -59,50 +71,116 @@ func getVersions(args []string, stdin io.Reader, ignore bool) ([]*goversion.Vers
 	return res, nil
 }
 
-func addVersion(ver string, ignore bool, versions []*goversion.Version) ([]*goversion.Version, error) {
-	v, err := goversion.NewVersion(ver)
+func addVersion(ver string, ignore bool, options *goversion.ParseOptions, versions []*goversion.Version) ([]*goversion.Version, error) {
+	v, err := goversion.NewVersionWithOptions(ver, options)
 	if err != nil {
 		if ignore {
 			return versions, nil
 		}
-		return nil, fmt.Errorf("could not parse version %q: %v", ver, err)
+		return nil, fmt.Errorf("could not parse candidate: %v", err)
 	}
 	return append(versions, v), nil
 }
 
 func main() {
 	k := kong.Parse(&cli,
-		kong.Vars{"version": version},
+		kong.Vars{"version": fmt.Sprintf("%s (embedded releases from %s)", version, goreleases.EmbeddedSnapshotTime().Format("2006-01-02"))},
 		kong.Description(strings.TrimSpace(description)),
 	)
 
-	c, err := goversion.NewConstraints(cli.Constraint)
 	if cli.ValidateConstraint {
+		if goversion.IsAlias(cli.Constraint) {
+			fmt.Println(strings.TrimSpace(cli.Constraint))
+			k.Exit(0)
+		}
+		c, err := getConstraints(nil)
 		if err != nil {
-			fmt.Fprintf(k.Stderr, "invalid constraint: %q\n", cli.Constraint)
+			fmt.Fprintln(k.Stderr, err)
 			k.Exit(1)
 		}
-		fmt.Println(c)
+		fmt.Println(c.Normalize())
+		fmt.Println(c.SemverString())
 		k.Exit(0)
 	}
-	k.FatalIfErrorf(err)
 
-	versions, err := getVersions(cli.Candidates, os.Stdin, cli.IgnoreInvalid)
+	if len(cli.Candidates) == 0 && !cli.Embedded {
+		k.Fatalf("candidates are required unless --embedded is set")
+	}
+
+	// Only aliases depend on the candidates, so anything else is checked
+	// before reading stdin.
+	var c *goversion.Constraints
+	var err error
+	if !goversion.IsAlias(cli.Constraint) {
+		c, err = getConstraints(nil)
+		k.FatalIfErrorf(err)
+	}
+
+	var parseOptions *goversion.ParseOptions
+	if cli.Lenient {
+		parseOptions = goversion.LenientParseOptions()
+	}
+	versions, err := getVersions(cli.Candidates, os.Stdin, cli.IgnoreInvalid, parseOptions)
 	k.FatalIfErrorf(err)
+	if cli.Embedded {
+		var releases []goreleases.Release
+		releases, err = goreleases.EmbeddedReleases()
+		k.FatalIfErrorf(err)
+		versions = append(versions, goreleases.Versions(releases)...)
+	}
+
+	if c == nil {
+		c, err = getConstraints(versions)
+		k.FatalIfErrorf(err)
+	}
+
+	if cli.Explain {
+		for _, v := range versions {
+			fmt.Print(c.ExplainIn(v, versions))
+		}
+		return
+	}
 
 	for _, s := range results(c, cli.MaxResults, versions) {
 		fmt.Println(s)
 	}
 }
 
-func results(c *goversion.Constraints, maxResults int, versions []*goversion.Version) []string {
-	candidates := make([]*goversion.Version, 0, len(versions))
-	for _, v := range versions {
-		if c.Check(v) {
-			candidates = append(candidates, v)
+// getConstraints returns the constraints from the command line. Aliases are
+// resolved against candidates.
+func getConstraints(candidates []*goversion.Version) (*goversion.Constraints, error) {
+	switch {
+	case cli.Gomod != "" && cli.Constraint != "":
+		return nil, fmt.Errorf("--constraint and --gomod can't be used together")
+	case cli.Constraint == "" && cli.Gomod == "":
+		return nil, fmt.Errorf("one of --constraint or --gomod is required")
+	}
+	prereleases, err := goversion.ParsePrereleasePolicy(cli.Prereleases)
+	if err != nil {
+		return nil, err
+	}
+	options := &goversion.ConstraintsOptions{
+		Prereleases: prereleases,
+	}
+	if cli.Gover {
+		options.Ordering = goversion.GoverOrdering
+	}
+	if cli.Gomod != "" {
+		mf, err := goversion.ReadModFile(cli.Gomod)
+		if err != nil {
+			return nil, err
 		}
+		return mf.ConstraintsWithOptions(options)
 	}
-	sort.Sort(sort.Reverse(goversion.Collection(candidates)))
+	return goversion.ResolveConstraints(cli.Constraint, candidates, options)
+}
+
+func results(c *goversion.Constraints, maxResults int, versions []*goversion.Version) []string {
+	candidates := goversion.Collection(versions).Filter(c)
+	ordering := c.Ordering()
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return ordering.Less(candidates[j], candidates[i])
+	})
 	if maxResults > 0 && maxResults < len(candidates) {
 		candidates = candidates[:maxResults]
 	}
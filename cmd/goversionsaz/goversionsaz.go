@@ -2,6 +2,7 @@ package main
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
@@ -10,6 +11,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/willabides/goversions/goreleases"
 	"github.com/willabides/goversions/goversion"
 )
 
@@ -19,9 +21,18 @@ func main() {
 		listenAddr = ":" + val
 	}
 	sMux := http.NewServeMux()
+	// start with the embedded releases so there is something to serve when
+	// versionsSource can't be reached
+	releases, err := goreleases.EmbeddedReleases()
+	if err != nil {
+		log.Fatal(err)
+	}
 	sMux.Handle("/api/goversion-select", &goVersionSelectHandler{
-		versionsMaxAge: 15 * time.Minute,
-		versionsSource: "https://raw.githubusercontent.com/WillAbides/goreleases/main/versions.txt",
+		versionsMaxAge:     15 * time.Minute,
+		versionsRetryDelay: time.Minute,
+		versionsSource:     "https://raw.githubusercontent.com/WillAbides/goreleases/main/versions.txt",
+		httpClient:         &http.Client{Timeout: 10 * time.Second},
+		versions:           goreleases.Versions(releases),
 	})
 	log.Printf("About to listen on %s. Try http://%s/api/goversion-select?constraint=1.x", listenAddr, listenAddr)
 	server := &http.Server{
@@ -33,11 +44,14 @@ func main() {
 }
 
 type goVersionSelectHandler struct {
-	versionsMaxAge time.Duration
-	versionsSource string
-	versionsMux    sync.Mutex
-	versionsTime   time.Time
-	versions       []*goversion.Version
+	versionsMaxAge     time.Duration
+	versionsRetryDelay time.Duration // how long to wait after a failed refresh
+	versionsSource     string
+	httpClient         *http.Client
+	versionsMux        sync.Mutex
+	versionsTime       time.Time
+	versionsFailTime   time.Time
+	versions           []*goversion.Version
 }
 
 func (h *goVersionSelectHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
@@ -45,25 +59,39 @@ func (h *goVersionSelectHandler) ServeHTTP(w http.ResponseWriter, req *http.Requ
 	if c == "" {
 		c = "1.x"
 	}
-	constraint, err := goversion.NewConstraints(c)
+	prereleases, err := goversion.ParsePrereleasePolicy(req.URL.Query().Get("prereleases"))
 	if err != nil {
-		http.Error(w, "invalid constraint", http.StatusBadRequest)
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	options := &goversion.ConstraintsOptions{
+		Prereleases: prereleases,
+	}
+	if !goversion.IsAlias(c) {
+		_, err = goversion.NewConstraintsWithOptions(c, options)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
 	versions, err := h.getVersions()
 	if err != nil {
-		http.Error(w, "", http.StatusInternalServerError)
+		if len(versions) == 0 {
+			http.Error(w, "", http.StatusInternalServerError)
+			return
+		}
+		log.Printf("using stale versions: %v", err)
+	}
+	constraint, err := goversion.ResolveConstraints(c, versions, options)
+	if errors.Is(err, goversion.ErrNoAliasMatch) {
+		http.Error(w, "no matching version found", http.StatusNotFound)
 		return
 	}
-	var result *goversion.Version
-	for _, v := range versions {
-		if !constraint.Check(v) {
-			continue
-		}
-		if result == nil || v.GreaterThan(result) {
-			result = v
-		}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
 	}
+	result := goversion.Collection(versions).Filter(constraint).Latest()
 	if result == nil {
 		http.Error(w, "no matching version found", http.StatusNotFound)
 		return
@@ -71,19 +99,39 @@ func (h *goVersionSelectHandler) ServeHTTP(w http.ResponseWriter, req *http.Requ
 	fmt.Fprintln(w, result.String())
 }
 
+// getVersions returns the versions from versionsSource. They are refreshed
+// when they are older than versionsMaxAge. After a failed refresh the current
+// versions are returned with the error, and the refresh isn't attempted again
+// for versionsRetryDelay.
 func (h *goVersionSelectHandler) getVersions() ([]*goversion.Version, error) {
 	h.versionsMux.Lock()
 	defer h.versionsMux.Unlock()
 	needsRefresh := h.versionsTime.IsZero() || time.Since(h.versionsTime) > h.versionsMaxAge
-	if !needsRefresh {
+	if !needsRefresh || time.Since(h.versionsFailTime) < h.versionsRetryDelay {
 		return h.versions, nil
 	}
-	resp, err := http.Get(h.versionsSource)
+	versions, err := h.fetchVersions()
 	if err != nil {
+		h.versionsFailTime = time.Now()
 		return h.versions, err
 	}
+	h.versions = versions
+	h.versionsTime = time.Now()
+	return h.versions, nil
+}
+
+func (h *goVersionSelectHandler) fetchVersions() ([]*goversion.Version, error) {
+	client := h.httpClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Get(h.versionsSource)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close() //nolint:errcheck // nothing to do with the error
 	if resp.StatusCode != 200 {
-		return h.versions, fmt.Errorf("not OK")
+		return nil, fmt.Errorf("not OK")
 	}
 	versions := make([]*goversion.Version, 0, len(h.versions))
 	scanner := bufio.NewScanner(resp.Body)
@@ -92,8 +140,7 @@ func (h *goVersionSelectHandler) getVersions() ([]*goversion.Version, error) {
 		if l == "" {
 			continue
 		}
-		var v *goversion.Version
-		v, err = goversion.NewVersion(l)
+		v, err := goversion.NewVersion(l)
 		if err != nil {
 			continue
 		}
@@ -101,9 +148,7 @@ func (h *goVersionSelectHandler) getVersions() ([]*goversion.Version, error) {
 	}
 	err = scanner.Err()
 	if err != nil {
-		return h.versions, err
+		return nil, err
 	}
-	h.versions = versions
-	h.versionsTime = time.Now()
-	return h.versions, resp.Body.Close()
+	return versions, nil
 }
@@ -8,6 +8,7 @@ require (
 	github.com/dnaeon/go-vcr v1.1.0
 	github.com/google/go-cmp v0.5.4
 	github.com/stretchr/testify v1.6.1
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -15,5 +16,4 @@ require (
 	github.com/pkg/errors v0.8.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	gopkg.in/yaml.v2 v2.4.0 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -0,0 +1,85 @@
+package goreleases
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// cacheEntry is the last successful response from a url along with the
+// validators needed to make a conditional request for it.
+type cacheEntry struct {
+	URL          string          `json:"url"`
+	ETag         string          `json:"etag,omitempty"`
+	LastModified string          `json:"last_modified,omitempty"`
+	Time         time.Time       `json:"time"`
+	Body         json.RawMessage `json:"body"`
+}
+
+// fresh tests if e is younger than maxAge.
+func (e *cacheEntry) fresh(maxAge time.Duration) bool {
+	return time.Since(e.Time) < maxAge
+}
+
+// releases decodes the cached releases.
+func (e *cacheEntry) releases() ([]Release, error) {
+	var releases []Release
+	err := json.Unmarshal(e.Body, &releases)
+	if err != nil {
+		return nil, err
+	}
+	return releases, nil
+}
+
+// cacheFile returns the file in dir that caches u.
+func cacheFile(dir, u string) string {
+	sum := sha256.Sum256([]byte(u))
+	return filepath.Join(dir, hex.EncodeToString(sum[:8])+".json")
+}
+
+// readCacheEntry returns the cached response for u or nil when there is no
+// usable entry.
+func readCacheEntry(dir, u string) *cacheEntry {
+	data, err := os.ReadFile(cacheFile(dir, u))
+	if err != nil {
+		return nil
+	}
+	var entry cacheEntry
+	err = json.Unmarshal(data, &entry)
+	if err != nil || entry.URL != u {
+		return nil
+	}
+	return &entry
+}
+
+// writeCacheEntry writes entry to dir. The file is replaced atomically so that
+// concurrent readers never see a partial entry.
+func writeCacheEntry(dir string, entry *cacheEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(dir, 0o700)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, "tmp-*")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.Write(data)
+	closeErr := tmp.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), cacheFile(dir, entry.URL))
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name()) //nolint:errcheck // the write already failed
+	}
+	return err
+}
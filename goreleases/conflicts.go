@@ -5,8 +5,30 @@ import (
 	"sort"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/willabides/goversions/goversion"
 )
 
+// versionComparer makes cmp tell apart versions like go1.21 and go1.21.0 that
+// are equal in order but are different releases.
+var versionComparer = cmp.Comparer(func(a, b *goversion.Version) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.String() == b.String()
+})
+
+// releaseComparer compares the invalid versions kept by Release and
+// ReleaseFile.
+var releaseComparer = cmp.AllowUnexported(Release{}, ReleaseFile{})
+
+// missingVersionMessage describes a release in side that has no valid version.
+func missingVersionMessage(side string, r Release) string {
+	if r.invalidVersion != "" {
+		return fmt.Sprintf("%s has a release with invalid version %q", side, r.invalidVersion)
+	}
+	return side + " has a release with no version"
+}
+
 // FindConflicts returns conflicts that would prevent automatically merging head into base.
 // Conflicts include missing releases in head and any change to an existing release.
 func FindConflicts(base, head []Release) []string {
@@ -14,39 +36,41 @@ func FindConflicts(base, head []Release) []string {
 	baseSeen := map[string]bool{}
 	headSeen := map[string]bool{}
 	for _, baseRelease := range base {
-		if baseRelease.Version == "" {
-			msgs = append(msgs, "base has a release with no version")
+		if baseRelease.Version == nil {
+			msgs = append(msgs, missingVersionMessage("base", baseRelease))
 			continue
 		}
-		if baseSeen[baseRelease.Version] {
-			msgs = append(msgs, fmt.Sprintf("base has multiple releases with version %q", baseRelease.Version))
+		baseVersion := baseRelease.Version.String()
+		if baseSeen[baseVersion] {
+			msgs = append(msgs, fmt.Sprintf("base has multiple releases with version %q", baseVersion))
 			continue
 		}
-		baseSeen[baseRelease.Version] = true
-		headRelease, ok := findReleaseByVersion(head, baseRelease.Version)
+		baseSeen[baseVersion] = true
+		headRelease, ok := findReleaseByVersion(head, baseVersion)
 		if !ok {
-			msgs = append(msgs, fmt.Sprintf("head is missing release %q", baseRelease.Version))
+			msgs = append(msgs, fmt.Sprintf("head is missing release %q", baseVersion))
 			continue
 		}
 		sort.Sort(releaseFileSorter(headRelease.Files))
 		sort.Sort(releaseFileSorter(baseRelease.Files))
 
-		if !cmp.Equal(baseRelease, headRelease) {
+		if !cmp.Equal(baseRelease, headRelease, versionComparer, releaseComparer) {
 			msgs = append(msgs, fmt.Sprintf("release %q differs:\n%s",
-				baseRelease.Version, cmp.Diff(baseRelease, headRelease)),
+				baseVersion, cmp.Diff(baseRelease, headRelease, versionComparer, releaseComparer)),
 			)
 		}
 	}
 	for _, headRelease := range head {
-		if headRelease.Version == "" {
-			msgs = append(msgs, "head has a release with no version")
+		if headRelease.Version == nil {
+			msgs = append(msgs, missingVersionMessage("head", headRelease))
 			continue
 		}
-		if headSeen[headRelease.Version] {
-			msgs = append(msgs, fmt.Sprintf("head has multiple releases with version %q", headRelease.Version))
+		headVersion := headRelease.Version.String()
+		if headSeen[headVersion] {
+			msgs = append(msgs, fmt.Sprintf("head has multiple releases with version %q", headVersion))
 			continue
 		}
-		headSeen[headRelease.Version] = true
+		headSeen[headVersion] = true
 	}
 	return msgs
 }
@@ -0,0 +1,58 @@
+package goreleases
+
+import (
+	"context"
+	"embed"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// snapshotFS holds a snapshot of go.dev release data. "go generate" writes
+// it as the golden test data.
+//
+//go:embed snapshot/releases.json snapshot/time
+var snapshotFS embed.FS
+
+var (
+	snapshotTimeOnce sync.Once
+	snapshotTime     time.Time
+)
+
+// EmbeddedSource returns a Source for the release data embedded in this
+// package. It works without network access but only knows about releases up
+// to EmbeddedSnapshotTime.
+func EmbeddedSource() Source {
+	return &FSSource{
+		FS:   snapshotFS,
+		Name: "snapshot/releases.json",
+	}
+}
+
+// EmbeddedReleases returns the release data embedded in this package sorted
+// from newest to oldest.
+func EmbeddedReleases() ([]Release, error) {
+	releases, err := EmbeddedSource().Releases(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	sort.Sort(sort.Reverse(releaseSorter(releases)))
+	return releases, nil
+}
+
+// EmbeddedSnapshotTime returns when the embedded release data was fetched
+// from go.dev.
+func EmbeddedSnapshotTime() time.Time {
+	snapshotTimeOnce.Do(func() {
+		data, err := snapshotFS.ReadFile("snapshot/time")
+		if err != nil {
+			panic(err)
+		}
+		snapshotTime, err = time.Parse(time.RFC3339, strings.TrimSpace(string(data)))
+		if err != nil {
+			panic(err)
+		}
+	})
+	return snapshotTime
+}
@@ -2,36 +2,221 @@ package goreleases
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"math"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 )
 
+// defaultMaxAttempts is how many times a request is attempted when the
+// server responds with a retryable status and no limit is set.
+const defaultMaxAttempts = 3
+
+// maxRetryDelay is the longest gldoClient waits between attempts. A server
+// asking for a longer wait with Retry-After gets the error instead.
+const maxRetryDelay = time.Minute
+
+// retryBaseDelay is the wait before the first retry when the server doesn't
+// send Retry-After. It doubles for each later retry.
+var retryBaseDelay = time.Second
+
+// bodyExcerptSize is how much of an error response HTTPError keeps.
+const bodyExcerptSize = 512
+
+// HTTPError is returned when go.dev or a mirror responds with a status other
+// than 200.
+type HTTPError struct {
+	StatusCode int
+	URL        string
+
+	// Body is the beginning of the response body.
+	Body string
+
+	retryAfter    time.Duration
+	hasRetryAfter bool
+}
+
+// Error implements error.
+func (e *HTTPError) Error() string {
+	msg := fmt.Sprintf("unexpected status %d %s from %s", e.StatusCode, http.StatusText(e.StatusCode), e.URL)
+	if body := strings.TrimSpace(e.Body); body != "" {
+		msg += ": " + body
+	}
+	return msg
+}
+
+// retryable tests if the request may succeed when retried.
+func (e *HTTPError) retryable() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500
+}
+
+// retryDelay returns how long to wait before the next attempt after attempt
+// attempts. It is false when the server asked for a wait longer than
+// maxRetryDelay.
+func (e *HTTPError) retryDelay(attempt int) (time.Duration, bool) {
+	if e.hasRetryAfter {
+		return e.retryAfter, e.retryAfter <= maxRetryDelay
+	}
+	delay := retryBaseDelay << (attempt - 1)
+	if delay > maxRetryDelay || delay <= 0 {
+		delay = maxRetryDelay
+	}
+	return delay, true
+}
+
+// parseRetryAfter parses the value of a Retry-After header, which is either a
+// number of seconds or a date.
+func parseRetryAfter(val string) (time.Duration, bool) {
+	if val == "" {
+		return 0, false
+	}
+	seconds, err := strconv.ParseInt(val, 10, 64)
+	if err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		// avoid overflowing time.Duration
+		if seconds > math.MaxInt64/int64(time.Second) {
+			seconds = math.MaxInt64 / int64(time.Second)
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	date, err := http.ParseTime(val)
+	if err != nil {
+		return 0, false
+	}
+	delay := time.Until(date)
+	if delay < 0 {
+		delay = 0
+	}
+	return delay, true
+}
+
 type gldoClient struct {
-	httpClient *http.Client
+	httpClient  *http.Client
+	url         string // defaults to GoDevURL
+	cacheDir    string // responses aren't cached when empty
+	cacheMaxAge time.Duration
+	maxAttempts int // defaults to defaultMaxAttempts
 }
 
 func (c *gldoClient) fetchReleases(ctx context.Context) ([]Release, error) {
+	u := c.url
+	if u == "" {
+		u = GoDevURL
+	}
+	var cached *cacheEntry
+	if c.cacheDir != "" {
+		cached = readCacheEntry(c.cacheDir, u)
+	}
+	if cached != nil && cached.fresh(c.cacheMaxAge) {
+		releases, err := cached.releases()
+		if err == nil {
+			return releases, nil
+		}
+		cached = nil
+	}
+	entry, err := c.get(ctx, u, cached)
+	var releases []Release
+	if err == nil {
+		releases, err = entry.releases()
+	}
+	if err != nil {
+		// fall back to stale data when upstream fails
+		if cached != nil && ctx.Err() == nil {
+			return cached.releases()
+		}
+		return nil, err
+	}
+	if c.cacheDir != "" {
+		_ = writeCacheEntry(c.cacheDir, entry) //nolint:errcheck // a cache that can't be written shouldn't fail the fetch
+	}
+	return releases, nil
+}
+
+// get requests u. When cached is not nil the request is conditional, and a
+// "304 Not Modified" response returns cached with an updated time. Responses
+// with a 5xx or 429 status are retried with exponential backoff or after the
+// wait the server asks for with Retry-After.
+func (c *gldoClient) get(ctx context.Context, u string, cached *cacheEntry) (*cacheEntry, error) {
+	maxAttempts := c.maxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = defaultMaxAttempts
+	}
+	for attempt := 1; ; attempt++ {
+		entry, err := c.getOnce(ctx, u, cached)
+		var httpErr *HTTPError
+		if err == nil || attempt >= maxAttempts || !errors.As(err, &httpErr) || !httpErr.retryable() {
+			return entry, err
+		}
+		delay, ok := httpErr.retryDelay(attempt)
+		if !ok {
+			return nil, err
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// getOnce is a single attempt of get.
+func (c *gldoClient) getOnce(ctx context.Context, u string, cached *cacheEntry) (*cacheEntry, error) {
 	httpClient := c.httpClient
 	if httpClient == nil {
 		httpClient = http.DefaultClient
 	}
-	u := `https://go.dev/dl/?mode=json&include=all`
 	req, err := http.NewRequestWithContext(ctx, "GET", u, http.NoBody)
 	if err != nil {
 		return nil, err
 	}
+	if cached != nil {
+		if cached.ETag != "" {
+			req.Header.Set("If-None-Match", cached.ETag)
+		}
+		if cached.LastModified != "" {
+			req.Header.Set("If-Modified-Since", cached.LastModified)
+		}
+	}
 	resp, err := httpClient.Do(req)
 	if err != nil {
 		return nil, err
 	}
+	defer resp.Body.Close() //nolint:errcheck // the body has been read or isn't needed
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		entry := *cached
+		entry.Time = time.Now()
+		return &entry, nil
+	}
 	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("not OK")
+		excerpt, err := io.ReadAll(io.LimitReader(resp.Body, bodyExcerptSize))
+		if err != nil {
+			return nil, err
+		}
+		httpErr := &HTTPError{
+			StatusCode: resp.StatusCode,
+			URL:        u,
+			Body:       string(excerpt),
+		}
+		httpErr.retryAfter, httpErr.hasRetryAfter = parseRetryAfter(resp.Header.Get("Retry-After"))
+		return nil, httpErr
 	}
-	var result []Release
-	err = json.NewDecoder(resp.Body).Decode(&result)
+	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return nil, err
 	}
-	return result, nil
+	return &cacheEntry{
+		URL:          u,
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		Time:         time.Now(),
+		Body:         body,
+	}, nil
 }
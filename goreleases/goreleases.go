@@ -3,74 +3,180 @@ package goreleases
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"sort"
+	"time"
 
 	"github.com/willabides/goversions/goversion"
 )
 
 // FetchReleasesOptions options for FetchReleases
 type FetchReleasesOptions struct {
-	HTTPClient   *http.Client
-	SkipVersions []string // go versions to skip ( like go1.7.2 which was pulled )
+	// Source is where releases come from. The default is an HTTPSource for
+	// go.dev using HTTPClient.
+	Source Source
+
+	// BaseURL is the base url of a go.dev mirror like "https://golang.google.cn".
+	// The default Source fetches from the mirror, and the URL of each returned
+	// file points at the mirror.
+	BaseURL string
+
+	// CacheDir is a directory where the default Source caches the last
+	// response from go.dev. The default is no caching. See
+	// HTTPSource.CacheDir.
+	CacheDir string
+
+	// CacheMaxAge is how long the default Source uses a cached response
+	// without checking whether it has changed. The default is to always check.
+	// It only matters when CacheDir is set.
+	CacheMaxAge time.Duration
+
+	// MaxAttempts limits how many times the default Source attempts a request
+	// when go.dev responds with a 5xx or 429 status. The default is 3.
+	MaxAttempts int
+
+	HTTPClient   *http.Client // only used when Source is nil
+	SkipVersions []string     // go versions to skip ( like go1.7.2 which was pulled )
 }
 
-// FetchReleases fetches release data from go.dev/dl
+// FetchReleases fetches release data from options.Source or go.dev/dl
 func FetchReleases(ctx context.Context, options *FetchReleasesOptions) ([]Release, error) {
-	httpClient := http.DefaultClient
 	if options == nil {
 		options = new(FetchReleasesOptions)
 	}
-	if options.HTTPClient != nil {
-		httpClient = options.HTTPClient
-	}
-	gc := &gldoClient{
-		httpClient: httpClient,
+	source := options.Source
+	if source == nil {
+		httpSource := &HTTPSource{
+			HTTPClient:  options.HTTPClient,
+			CacheDir:    options.CacheDir,
+			CacheMaxAge: options.CacheMaxAge,
+			MaxAttempts: options.MaxAttempts,
+		}
+		if options.BaseURL != "" {
+			httpSource.URL = releasesURL(options.BaseURL)
+		}
+		source = httpSource
 	}
-	releases, err := gc.fetchReleases(ctx)
+	releases, err := source.Releases(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("error fetching releases: %v", err)
+		return nil, fmt.Errorf("error fetching releases: %w", err)
 	}
 	filtered := make([]Release, 0, len(releases))
 	for _, r := range releases {
 		if skipVersion(r.Version, options.SkipVersions) {
 			continue
 		}
+		if options.BaseURL != "" {
+			r.Files = append([]ReleaseFile(nil), r.Files...)
+			for i := range r.Files {
+				r.Files[i].URL = fileURL(options.BaseURL, r.Files[i].Filename)
+			}
+		}
 		filtered = append(filtered, r)
 	}
 	sort.Sort(sort.Reverse(releaseSorter(filtered)))
 	return filtered, nil
 }
 
-func goVersionLess(a, b string) bool {
-	var invalidA, invalidB bool
-	verA, err := goversion.NewVersion(a)
-	if err != nil {
-		invalidA = true
-	}
-	verB, err := goversion.NewVersion(b)
-	if err != nil {
-		invalidB = true
+// Versions returns the versions of releases. Releases without a version are
+// skipped.
+func Versions(releases []Release) []*goversion.Version {
+	result := make([]*goversion.Version, 0, len(releases))
+	for _, r := range releases {
+		if r.Version == nil {
+			continue
+		}
+		result = append(result, r.Version)
 	}
-	if invalidB {
+	return result
+}
+
+// ResolveConstraints resolves a constraint or alias like "stable" against
+// releases. See goversion.ResolveConstraints.
+func ResolveConstraints(c string, releases []Release, options *goversion.ConstraintsOptions) (*goversion.Constraints, error) {
+	return goversion.ResolveConstraints(c, Versions(releases), options)
+}
+
+// goVersionLess orders versions with missing versions, which are nil, first.
+func goVersionLess(a, b *goversion.Version) bool {
+	if b == nil {
 		return false
 	}
-	if invalidA {
+	if a == nil {
 		return true
 	}
-	return verA.LessThan(verB)
+	return goversion.Compare(a, b) < 0
 }
 
-// Release is a go release
+// parseVersionOrNil returns the version of s or nil when it isn't valid. The
+// invalid version is returned so it can be written back out.
+func parseVersionOrNil(s string) (v *goversion.Version, invalid string) {
+	v, err := goversion.NewVersion(s)
+	if err != nil {
+		return nil, s
+	}
+	return v, ""
+}
+
+// versionJSON returns the value to encode for a version that was unmarshaled
+// from invalid.
+func versionJSON(v *goversion.Version, invalid string) interface{} {
+	if v == nil && invalid != "" {
+		return invalid
+	}
+	return v
+}
+
+// Release is a go release. Version is nil when the release data has an invalid
+// version. The invalid version is kept and written back out by MarshalJSON.
 type Release struct {
-	Version string        `json:"version"`
-	Stable  bool          `json:"stable"`
-	Files   []ReleaseFile `json:"files"`
+	Version *goversion.Version `json:"version"`
+	Stable  bool               `json:"stable"`
+	Files   []ReleaseFile      `json:"files"`
+
+	invalidVersion string
 }
 
-func (f Release) less(other Release) bool {
-	return goVersionLess(f.Version, other.Version)
+// InvalidVersion returns the version r was unmarshaled from when it isn't a
+// valid go version. It is empty when Version is set.
+func (r Release) InvalidVersion() string {
+	if r.Version != nil {
+		return ""
+	}
+	return r.invalidVersion
+}
+
+// MarshalJSON implements json.Marshaler. A release with an invalid version is
+// written with the version it was unmarshaled from.
+func (r Release) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Version interface{}   `json:"version"`
+		Stable  bool          `json:"stable"`
+		Files   []ReleaseFile `json:"files"`
+	}{
+		Version: versionJSON(r.Version, r.invalidVersion),
+		Stable:  r.Stable,
+		Files:   r.Files,
+	})
+}
+
+// UnmarshalJSON implements json.Unmarshaler. An invalid version leaves
+// r.Version nil instead of failing. See InvalidVersion.
+func (r *Release) UnmarshalJSON(data []byte) error {
+	type release Release
+	var aux struct {
+		release
+		Version string `json:"version"`
+	}
+	err := json.Unmarshal(data, &aux)
+	if err != nil {
+		return err
+	}
+	*r = Release(aux.release)
+	r.Version, r.invalidVersion = parseVersionOrNil(aux.Version)
+	return nil
 }
 
 type releaseSorter []Release
@@ -80,34 +186,91 @@ func (r releaseSorter) Len() int {
 }
 
 func (r releaseSorter) Less(i, j int) bool {
-	return r[i].less(r[j])
+	return goVersionLess(r[i].Version, r[j].Version)
 }
 
 func (r releaseSorter) Swap(i, j int) {
 	r[i], r[j] = r[j], r[i]
 }
 
-// ReleaseFile is a file included in a go release
+// ReleaseFile is a file included in a go release. Version is nil when the
+// release data has an invalid version. The invalid version is kept and written
+// back out by MarshalJSON.
 type ReleaseFile struct {
-	Filename string `json:"filename"`
-	OS       string `json:"os"`
-	Arch     string `json:"arch"`
-	Version  string `json:"version"`
-	Sha256   string `json:"sha256"`
-	Size     int64  `json:"size"`
-	Kind     string `json:"kind"`
+	Filename string             `json:"filename"`
+	OS       string             `json:"os"`
+	Arch     string             `json:"arch"`
+	Version  *goversion.Version `json:"version"`
+	Sha256   string             `json:"sha256"`
+	Size     int64              `json:"size"`
+	Kind     string             `json:"kind"`
+
+	// URL is where to download the file when it isn't go.dev. Use DownloadURL
+	// to get the url either way.
+	URL string `json:"url,omitempty"`
+
+	invalidVersion string
 }
 
-func (f ReleaseFile) less(other ReleaseFile) bool {
-	if goVersionLess(f.Version, other.Version) {
-		return true
+// InvalidVersion returns the version f was unmarshaled from when it isn't a
+// valid go version. It is empty when Version is set.
+func (f ReleaseFile) InvalidVersion() string {
+	if f.Version != nil {
+		return ""
 	}
-	if goVersionLess(other.Version, f.Version) {
-		return false
+	return f.invalidVersion
+}
+
+// MarshalJSON implements json.Marshaler. A file with an invalid version is
+// written with the version it was unmarshaled from.
+func (f ReleaseFile) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Filename string      `json:"filename"`
+		OS       string      `json:"os"`
+		Arch     string      `json:"arch"`
+		Version  interface{} `json:"version"`
+		Sha256   string      `json:"sha256"`
+		Size     int64       `json:"size"`
+		Kind     string      `json:"kind"`
+		URL      string      `json:"url,omitempty"`
+	}{
+		Filename: f.Filename,
+		OS:       f.OS,
+		Arch:     f.Arch,
+		Version:  versionJSON(f.Version, f.invalidVersion),
+		Sha256:   f.Sha256,
+		Size:     f.Size,
+		Kind:     f.Kind,
+		URL:      f.URL,
+	})
+}
+
+// UnmarshalJSON implements json.Unmarshaler. An invalid version leaves
+// f.Version nil instead of failing. See InvalidVersion.
+func (f *ReleaseFile) UnmarshalJSON(data []byte) error {
+	type releaseFile ReleaseFile
+	var aux struct {
+		releaseFile
+		Version string `json:"version"`
+	}
+	err := json.Unmarshal(data, &aux)
+	if err != nil {
+		return err
+	}
+	*f = ReleaseFile(aux.releaseFile)
+	f.Version, f.invalidVersion = parseVersionOrNil(aux.Version)
+	return nil
+}
+
+// DownloadURL returns the url to download f from.
+func (f ReleaseFile) DownloadURL() string {
+	if f.URL != "" {
+		return f.URL
 	}
-	return f.Filename < other.Filename
+	return fileURL(DefaultBaseURL, f.Filename)
 }
 
+// releaseFileSorter sorts files by version and then by filename.
 type releaseFileSorter []ReleaseFile
 
 func (r releaseFileSorter) Len() int {
@@ -115,16 +278,25 @@ func (r releaseFileSorter) Len() int {
 }
 
 func (r releaseFileSorter) Less(i, j int) bool {
-	return r[i].less(r[j])
+	if goVersionLess(r[i].Version, r[j].Version) {
+		return true
+	}
+	if goVersionLess(r[j].Version, r[i].Version) {
+		return false
+	}
+	return r[i].Filename < r[j].Filename
 }
 
 func (r releaseFileSorter) Swap(i, j int) {
 	r[i], r[j] = r[j], r[i]
 }
 
-func skipVersion(version string, skips []string) bool {
+func skipVersion(version *goversion.Version, skips []string) bool {
+	if version == nil {
+		return false
+	}
 	for _, skip := range skips {
-		if skip == version {
+		if skip == version.String() {
 			return true
 		}
 	}
@@ -133,7 +305,7 @@ func skipVersion(version string, skips []string) bool {
 
 func findReleaseByVersion(releases []Release, version string) (Release, bool) {
 	for _, release := range releases {
-		if release.Version == version {
+		if release.Version != nil && release.Version.String() == version {
 			return release, true
 		}
 	}
@@ -3,20 +3,32 @@ package goreleases
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"flag"
+	"fmt"
+	"io"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"testing"
+	"testing/fstest"
+	"time"
 
 	"github.com/dnaeon/go-vcr/recorder"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/willabides/goversions/goversion"
 )
 
 var (
 	updateVCR    = flag.Bool("update-vcr", false, "Set to update VCR recordings")
 	updateGolden = flag.Bool("write-golden", false, "Set to update golden files")
+
+	// goldenFile is also the snapshot embedded by EmbeddedSource.
+	goldenFile = filepath.FromSlash("snapshot/releases.json")
 )
 
 //go:generate go test . -write-golden
@@ -45,27 +57,62 @@ func testHTTPClient(t *testing.T, cassette string) *http.Client {
 	}
 }
 
+// dateTransport records the Date header of the last response.
+type dateTransport struct {
+	transport http.RoundTripper
+	date      string
+}
+
+func (d *dateTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := d.transport.RoundTrip(req)
+	if err == nil {
+		d.date = resp.Header.Get("Date")
+	}
+	return resp, err
+}
+
 func TestFetchRelease(t *testing.T) {
 	t.Run("golden", func(t *testing.T) {
+		transport := &dateTransport{
+			transport: vcr(t, "testdata/vcr/gldo_client_default"),
+		}
 		releases, err := FetchReleases(context.Background(), &FetchReleasesOptions{
-			HTTPClient:   testHTTPClient(t, ""),
+			HTTPClient:   &http.Client{Transport: transport},
 			SkipVersions: []string{"go1.7.2"},
 		})
 		require.NoError(t, err)
 		encoded, err := json.MarshalIndent(&releases, "", " ")
 		require.NoError(t, err)
-		goldenFile := filepath.FromSlash("testdata/golden/releases.json")
+		date, err := http.ParseTime(transport.date)
+		require.NoError(t, err)
+		snapshotTime := date.UTC().Format(time.RFC3339) + "\n"
+		snapshotTimeFile := filepath.FromSlash("snapshot/time")
 		if updateGolden != nil && *updateGolden {
-			require.NoError(t, os.MkdirAll(filepath.Dir(goldenFile), 0o700))
 			err = os.WriteFile(goldenFile, encoded, 0o600)
 			require.NoError(t, err)
+			err = os.WriteFile(snapshotTimeFile, []byte(snapshotTime), 0o600)
+			require.NoError(t, err)
 		}
 		want, err := os.ReadFile(goldenFile)
 		require.NoError(t, err)
 		require.Equal(t, string(want), string(encoded))
+		want, err = os.ReadFile(snapshotTimeFile)
+		require.NoError(t, err)
+		require.Equal(t, string(want), snapshotTime)
 	})
 }
 
+func TestEmbeddedReleases(t *testing.T) {
+	golden, err := os.ReadFile(goldenFile)
+	require.NoError(t, err)
+	var want []Release
+	require.NoError(t, json.Unmarshal(golden, &want))
+	got, err := EmbeddedReleases()
+	require.NoError(t, err)
+	require.Equal(t, want, got)
+	require.Equal(t, time.Date(2021, 8, 20, 19, 40, 49, 0, time.UTC), EmbeddedSnapshotTime())
+}
+
 func TestFindConflicts(t *testing.T) {
 	t.Run("no changes", func(t *testing.T) {
 		ctx := context.Background()
@@ -101,3 +148,416 @@ func TestFindConflicts(t *testing.T) {
 		require.Equal(t, want, got)
 	})
 }
+
+func TestFindConflicts_changedRelease(t *testing.T) {
+	var base, head []Release
+	data := `[{"version":"go1.17","stable":true,"files":[{"filename":"go1.17.src.tar.gz","version":"go1.17","sha256":"abc"}]}]`
+	require.NoError(t, json.Unmarshal([]byte(data), &base))
+	require.NoError(t, json.Unmarshal([]byte(data), &head))
+	require.Empty(t, FindConflicts(base, head))
+	head[0].Files[0].Sha256 = "def"
+	got := FindConflicts(base, head)
+	require.Len(t, got, 1)
+	require.Contains(t, got[0], `release "go1.17" differs`)
+}
+
+func TestFindConflicts_renamedRelease(t *testing.T) {
+	var base, head []Release
+	require.NoError(t, json.Unmarshal([]byte(`[{"version":"go1.21","stable":true}]`), &base))
+	require.NoError(t, json.Unmarshal([]byte(`[{"version":"go1.21.0","stable":true}]`), &head))
+	require.Equal(t, []string{`head is missing release "go1.21"`}, FindConflicts(base, head))
+}
+
+func TestRelease_invalidVersion(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "releases.json")
+	data := `[{"version":"1.x","stable":true},{"version":"go1.21.0","stable":true}]`
+	require.NoError(t, os.WriteFile(filename, []byte(data), 0o600))
+	releases, err := FetchReleases(context.Background(), &FetchReleasesOptions{
+		Source: &FileSource{Filename: filename},
+	})
+	require.NoError(t, err)
+	require.Len(t, releases, 2)
+	require.Equal(t, "go1.21.0", releases[0].Version.String())
+	require.Nil(t, releases[1].Version)
+	versions := Versions(releases)
+	require.Len(t, versions, 1)
+	require.Equal(t, "go1.21.0", versions[0].String())
+}
+
+func TestRelease_invalidVersionRoundTrip(t *testing.T) {
+	data := `[{"version":"1.x","stable":true,"files":[{"filename":"go1.x.src.tar.gz","os":"","arch":"","version":"1.x","sha256":"abc","size":1,"kind":"source"}]}]`
+	var releases []Release
+	require.NoError(t, json.Unmarshal([]byte(data), &releases))
+	require.Nil(t, releases[0].Version)
+	require.Equal(t, "1.x", releases[0].InvalidVersion())
+	require.Equal(t, "1.x", releases[0].Files[0].InvalidVersion())
+	got, err := json.Marshal(releases)
+	require.NoError(t, err)
+	require.JSONEq(t, data, string(got))
+
+	require.Equal(t, []string{`head has a release with invalid version "1.x"`}, FindConflicts(nil, releases))
+	var head []Release
+	require.NoError(t, json.Unmarshal([]byte(strings.ReplaceAll(data, `"1.x"`, `"2.x"`)), &head))
+	require.Equal(t, []string{
+		`base has a release with invalid version "1.x"`,
+		`head has a release with invalid version "2.x"`,
+	}, FindConflicts(releases, head))
+}
+
+func TestReleaseSorter(t *testing.T) {
+	var releases []Release
+	data := `[{"version":"go1.21rc10"},{"version":"go1.20"},{"version":"1.x"},{"version":"go1.21rc2"}]`
+	require.NoError(t, json.Unmarshal([]byte(data), &releases))
+	sort.Sort(releaseSorter(releases))
+	var got []string
+	for _, r := range releases {
+		if r.Version == nil {
+			got = append(got, "")
+			continue
+		}
+		got = append(got, r.Version.String())
+	}
+	require.Equal(t, []string{"", "go1.20", "go1.21rc2", "go1.21rc10"}, got)
+}
+
+func BenchmarkReleaseSorter(b *testing.B) {
+	data, err := os.ReadFile(goldenFile)
+	require.NoError(b, err)
+	var releases []Release
+	require.NoError(b, json.Unmarshal(data, &releases))
+	sorted := make([]Release, len(releases))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		copy(sorted, releases)
+		sort.Sort(releaseSorter(sorted))
+	}
+}
+
+func TestReleaseFile_ToolchainModule(t *testing.T) {
+	data, err := os.ReadFile(goldenFile)
+	require.NoError(t, err)
+	var releases []Release
+	require.NoError(t, json.Unmarshal(data, &releases))
+	release, ok := findReleaseByVersion(releases, "go1.17")
+	require.True(t, ok)
+	modules := map[string]string{}
+	for _, f := range release.Files {
+		m, ok := f.ToolchainModule()
+		if ok {
+			modules[f.Filename] = m.ModuleVersion()
+		}
+	}
+	assert.Equal(t, "v0.0.1-go1.17.linux-amd64", modules["go1.17.linux-amd64.tar.gz"])
+	assert.Equal(t, "v0.0.1-go1.17.linux-arm", modules["go1.17.linux-armv6l.tar.gz"])
+	assert.Equal(t, "v0.0.1-go1.17.windows-386", modules["go1.17.windows-386.zip"])
+	assert.NotContains(t, modules, "go1.17.src.tar.gz")
+	assert.NotContains(t, modules, "go1.17.windows-amd64.msi")
+
+	m, err := goversion.ParseToolchainModule("golang.org/toolchain@v0.0.1-go1.17.linux-arm")
+	require.NoError(t, err)
+	f, ok := ToolchainModuleFile(releases, m)
+	require.True(t, ok)
+	assert.Equal(t, "go1.17.linux-armv6l.tar.gz", f.Filename)
+
+	m, err = goversion.ParseToolchainModule("v0.0.1-go1.17.plan9-amd64")
+	require.NoError(t, err)
+	_, ok = ToolchainModuleFile(releases, m)
+	assert.False(t, ok)
+}
+
+func TestSources(t *testing.T) {
+	golden, err := os.ReadFile(goldenFile)
+	require.NoError(t, err)
+	var want []Release
+	require.NoError(t, json.Unmarshal(golden, &want))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/releases.json" {
+			http.NotFound(w, r)
+			return
+		}
+		_, err := w.Write(golden)
+		assert.NoError(t, err)
+	}))
+	t.Cleanup(server.Close)
+
+	ctx := context.Background()
+	for name, source := range map[string]Source{
+		"file":   &FileSource{Filename: goldenFile},
+		"fs":     &FSSource{FS: fstest.MapFS{"releases.json": {Data: golden}}, Name: "releases.json"},
+		"mirror": &HTTPSource{URL: server.URL + "/releases.json"},
+		"fallback": FallbackSource{
+			&FileSource{Filename: filepath.FromSlash("testdata/missing.json")},
+			&HTTPSource{URL: server.URL + "/missing.json"},
+			&FileSource{Filename: goldenFile},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			got, err := source.Releases(ctx)
+			require.NoError(t, err)
+			require.Equal(t, want, got)
+		})
+	}
+
+	t.Run("fallback errors", func(t *testing.T) {
+		_, err := FallbackSource{
+			&FileSource{Filename: filepath.FromSlash("testdata/missing.json")},
+			&FSSource{FS: fstest.MapFS{"bad.json": {Data: []byte(`[{"version":1}]`)}}, Name: "bad.json"},
+		}.Releases(ctx)
+		require.True(t, errors.Is(err, os.ErrNotExist))
+		var typeErr *json.UnmarshalTypeError
+		require.True(t, errors.As(err, &typeErr))
+		_, err = FallbackSource{}.Releases(ctx)
+		require.Error(t, err)
+	})
+
+	t.Run("FetchReleases", func(t *testing.T) {
+		got, err := FetchReleases(ctx, &FetchReleasesOptions{
+			Source:       &FileSource{Filename: goldenFile},
+			SkipVersions: []string{"go1.17"},
+		})
+		require.NoError(t, err)
+		require.Len(t, got, len(want)-1)
+		_, ok := findReleaseByVersion(got, "go1.17")
+		require.False(t, ok)
+	})
+}
+
+func TestFetchReleases_baseURL(t *testing.T) {
+	golden, err := os.ReadFile(goldenFile)
+	require.NoError(t, err)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/dl/" || r.URL.Query().Get("mode") != "json" || r.URL.Query().Get("include") != "all" {
+			http.NotFound(w, r)
+			return
+		}
+		_, err := w.Write(golden)
+		assert.NoError(t, err)
+	}))
+	t.Cleanup(server.Close)
+
+	releases, err := FetchReleases(context.Background(), &FetchReleasesOptions{
+		BaseURL: server.URL + "/",
+	})
+	require.NoError(t, err)
+	release, ok := findReleaseByVersion(releases, "go1.17")
+	require.True(t, ok)
+	f := release.Files[0]
+	require.Equal(t, server.URL+"/dl/"+f.Filename, f.URL)
+	require.Equal(t, f.URL, f.DownloadURL())
+
+	f.URL = ""
+	require.Equal(t, "https://go.dev/dl/"+f.Filename, f.DownloadURL())
+}
+
+func TestFetchReleases_cache(t *testing.T) {
+	var (
+		body         = `[{"version":"go1.21.0","stable":true,"files":[]}]`
+		etag         = `"v1"`
+		lastModified = "Tue, 08 Aug 2023 16:00:00 GMT"
+		fail         bool
+		requests     int
+		notModified  int
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if fail {
+			http.Error(w, "oops", http.StatusInternalServerError)
+			return
+		}
+		if r.Header.Get("If-None-Match") == etag && r.Header.Get("If-Modified-Since") == lastModified {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Last-Modified", lastModified)
+		_, err := w.Write([]byte(body))
+		assert.NoError(t, err)
+	}))
+	t.Cleanup(server.Close)
+
+	ctx := context.Background()
+	cacheDir := filepath.Join(t.TempDir(), "cache")
+	fetch := func(t *testing.T, maxAge time.Duration) []string {
+		t.Helper()
+		releases, err := (&HTTPSource{
+			URL:         server.URL,
+			CacheDir:    cacheDir,
+			CacheMaxAge: maxAge,
+			MaxAttempts: 1,
+		}).Releases(ctx)
+		require.NoError(t, err)
+		var got []string
+		for _, r := range releases {
+			got = append(got, r.Version.String())
+		}
+		return got
+	}
+
+	require.Equal(t, []string{"go1.21.0"}, fetch(t, 0))
+	require.Equal(t, 1, requests)
+
+	// revalidates with a conditional request
+	require.Equal(t, []string{"go1.21.0"}, fetch(t, 0))
+	require.Equal(t, 2, requests)
+	require.Equal(t, 1, notModified)
+
+	// serves from the cache within max-age
+	require.Equal(t, []string{"go1.21.0"}, fetch(t, time.Hour))
+	require.Equal(t, 2, requests)
+
+	// falls back to the cache when upstream fails
+	fail = true
+	require.Equal(t, []string{"go1.21.0"}, fetch(t, 0))
+	require.Equal(t, 3, requests)
+	_, err := (&HTTPSource{URL: server.URL, MaxAttempts: 1}).Releases(ctx)
+	require.Error(t, err)
+
+	// picks up changes
+	fail = false
+	body, etag = `[{"version":"go1.21.1","stable":true,"files":[]}]`, `"v2"`
+	require.Equal(t, []string{"go1.21.1"}, fetch(t, 0))
+	require.Equal(t, 1, notModified)
+
+	// FetchReleases passes the cache options to the default source
+	releases, err := FetchReleases(ctx, &FetchReleasesOptions{
+		BaseURL:     "http://127.0.0.1:0",
+		CacheDir:    cacheDir,
+		CacheMaxAge: time.Hour,
+	})
+	require.Error(t, err)
+	require.Nil(t, releases)
+	require.NoError(t, writeCacheEntry(cacheDir, &cacheEntry{
+		URL:  releasesURL("http://127.0.0.1:0"),
+		Time: time.Now(),
+		Body: []byte(body),
+	}))
+	releases, err = FetchReleases(ctx, &FetchReleasesOptions{
+		BaseURL:     "http://127.0.0.1:0",
+		CacheDir:    cacheDir,
+		CacheMaxAge: time.Hour,
+	})
+	require.NoError(t, err)
+	require.Len(t, releases, 1)
+}
+
+// closeTransport counts the response bodies that are closed.
+type closeTransport struct {
+	opened, closed int
+}
+
+func (c *closeTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := http.DefaultTransport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	c.opened++
+	resp.Body = &closeCounter{ReadCloser: resp.Body, closed: &c.closed}
+	return resp, nil
+}
+
+type closeCounter struct {
+	io.ReadCloser
+	closed *int
+}
+
+func (c *closeCounter) Close() error {
+	*c.closed++
+	return c.ReadCloser.Close()
+}
+
+func TestHTTPSource_retries(t *testing.T) {
+	oldDelay := retryBaseDelay
+	retryBaseDelay = time.Millisecond
+	t.Cleanup(func() {
+		retryBaseDelay = oldDelay
+	})
+
+	var (
+		statuses   []int
+		retryAfter string
+		requests   int
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if len(statuses) > 0 {
+			status := statuses[0]
+			statuses = statuses[1:]
+			w.Header().Set("Retry-After", retryAfter)
+			http.Error(w, strings.Repeat("oops ", 200), status)
+			return
+		}
+		_, err := w.Write([]byte(`[{"version":"go1.21.0","stable":true,"files":[]}]`))
+		assert.NoError(t, err)
+	}))
+	t.Cleanup(server.Close)
+
+	ctx := context.Background()
+	for _, td := range []struct {
+		name         string
+		statuses     []int
+		retryAfter   string
+		maxAttempts  int
+		wantRequests int
+		wantStatus   int
+	}{
+		{name: "recovers", statuses: []int{503, 502}, wantRequests: 3},
+		{name: "too many requests", statuses: []int{429}, retryAfter: "0", wantRequests: 2},
+		{name: "gives up", statuses: []int{500, 500, 500}, wantRequests: 3, wantStatus: 500},
+		{name: "attempt limit", statuses: []int{500, 500}, maxAttempts: 2, wantRequests: 2, wantStatus: 500},
+		{name: "not retryable", statuses: []int{404}, wantRequests: 1, wantStatus: 404},
+		{name: "retry after too long", statuses: []int{503}, retryAfter: "3600", wantRequests: 1, wantStatus: 503},
+	} {
+		t.Run(td.name, func(t *testing.T) {
+			statuses, retryAfter, requests = td.statuses, td.retryAfter, 0
+			transport := &closeTransport{}
+			releases, err := FetchReleases(ctx, &FetchReleasesOptions{
+				BaseURL:     server.URL,
+				MaxAttempts: td.maxAttempts,
+				HTTPClient:  &http.Client{Transport: transport},
+			})
+			require.Equal(t, td.wantRequests, requests)
+			require.Equal(t, transport.opened, transport.closed)
+			if td.wantStatus == 0 {
+				require.NoError(t, err)
+				require.Len(t, releases, 1)
+				return
+			}
+			var httpErr *HTTPError
+			require.True(t, errors.As(err, &httpErr))
+			require.Equal(t, td.wantStatus, httpErr.StatusCode)
+			require.Equal(t, releasesURL(server.URL), httpErr.URL)
+			require.Len(t, httpErr.Body, bodyExcerptSize)
+			require.True(t, strings.HasPrefix(httpErr.Body, "oops oops"))
+			require.Contains(t, err.Error(), fmt.Sprintf("unexpected status %d", td.wantStatus))
+		})
+	}
+
+	t.Run("canceled while waiting", func(t *testing.T) {
+		retryBaseDelay = time.Hour
+		statuses, retryAfter, requests = []int{503}, "", 0
+		ctx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+		defer cancel()
+		_, err := (&HTTPSource{URL: server.URL}).Releases(ctx)
+		require.True(t, errors.Is(err, context.DeadlineExceeded))
+		require.Equal(t, 1, requests)
+	})
+}
+
+func Test_parseRetryAfter(t *testing.T) {
+	got, ok := parseRetryAfter("120")
+	require.True(t, ok)
+	require.Equal(t, 2*time.Minute, got)
+	got, ok = parseRetryAfter("99999999999999999")
+	require.True(t, ok)
+	require.True(t, got > maxRetryDelay)
+	got, ok = parseRetryAfter(time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat))
+	require.True(t, ok)
+	require.Zero(t, got)
+	for _, val := range []string{"", "-1", "soon"} {
+		_, ok = parseRetryAfter(val)
+		require.False(t, ok, val)
+	}
+}
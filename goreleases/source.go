@@ -0,0 +1,139 @@
+package goreleases
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// DefaultBaseURL is the base url of go.dev.
+const DefaultBaseURL = `https://go.dev`
+
+// GoDevURL is the url of the release data on go.dev.
+const GoDevURL = DefaultBaseURL + releasesPath
+
+// releasesPath is where go.dev and its mirrors serve release data relative to
+// their base url.
+const releasesPath = `/dl/?mode=json&include=all`
+
+// releasesURL returns the url of the release data on the site at baseURL.
+func releasesURL(baseURL string) string {
+	return strings.TrimSuffix(baseURL, "/") + releasesPath
+}
+
+// fileURL returns the download url of filename on the site at baseURL.
+func fileURL(baseURL, filename string) string {
+	return strings.TrimSuffix(baseURL, "/") + "/dl/" + filename
+}
+
+// Source provides go release data in the format go.dev/dl serves.
+type Source interface {
+	Releases(ctx context.Context) ([]Release, error)
+}
+
+// HTTPSource is a Source that downloads release data from go.dev or a mirror
+// serving the same json.
+type HTTPSource struct {
+	// URL is where to get the release json. The default is GoDevURL.
+	URL string
+
+	// HTTPClient is the client for requests. The default is
+	// http.DefaultClient.
+	HTTPClient *http.Client
+
+	// CacheDir is a directory for caching the last response. When it is set,
+	// requests are conditional on the cached response's ETag and
+	// Last-Modified headers, and the cached response is used when the server
+	// can't be reached or returns an error.
+	CacheDir string
+
+	// CacheMaxAge is how long a cached response is used without checking
+	// whether it has changed. The default is to always check.
+	CacheMaxAge time.Duration
+
+	// MaxAttempts limits how many times a request is attempted when the server
+	// responds with a 5xx or 429 status. The default is 3.
+	MaxAttempts int
+}
+
+// Releases implements Source.
+func (s *HTTPSource) Releases(ctx context.Context) ([]Release, error) {
+	gc := &gldoClient{
+		httpClient:  s.HTTPClient,
+		url:         s.URL,
+		cacheDir:    s.CacheDir,
+		cacheMaxAge: s.CacheMaxAge,
+		maxAttempts: s.MaxAttempts,
+	}
+	return gc.fetchReleases(ctx)
+}
+
+// FileSource is a Source that reads release data from a local file like one
+// written by "goreleases fetch".
+type FileSource struct {
+	Filename string
+}
+
+// Releases implements Source.
+func (s *FileSource) Releases(context.Context) ([]Release, error) {
+	data, err := os.ReadFile(s.Filename) //nolint:gosec // reading user-provided files is the point
+	if err != nil {
+		return nil, err
+	}
+	return decodeReleases(s.Filename, data)
+}
+
+// FSSource is a Source that reads release data from a file in a fs.FS. Use it
+// with an embed.FS to build an offline snapshot into a binary.
+type FSSource struct {
+	FS   fs.FS
+	Name string
+}
+
+// Releases implements Source.
+func (s *FSSource) Releases(context.Context) ([]Release, error) {
+	data, err := fs.ReadFile(s.FS, s.Name)
+	if err != nil {
+		return nil, err
+	}
+	return decodeReleases(s.Name, data)
+}
+
+// FallbackSource is a Source that tries each of its sources in order and
+// returns the releases from the first one that succeeds. When every source
+// fails the error holds all of their errors.
+type FallbackSource []Source
+
+// Releases implements Source.
+func (s FallbackSource) Releases(ctx context.Context) ([]Release, error) {
+	if len(s) == 0 {
+		return nil, fmt.Errorf("no sources")
+	}
+	errs := make([]error, 0, len(s))
+	for _, src := range s {
+		releases, err := src.Releases(ctx)
+		if err == nil {
+			return releases, nil
+		}
+		errs = append(errs, err)
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return nil, errors.Join(errs...)
+}
+
+func decodeReleases(name string, data []byte) ([]Release, error) {
+	var releases []Release
+	err := json.Unmarshal(data, &releases)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return releases, nil
+}
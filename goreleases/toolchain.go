@@ -0,0 +1,44 @@
+package goreleases
+
+import (
+	"github.com/willabides/goversions/goversion"
+)
+
+// dlArchs maps the architecture names go.dev/dl uses in file names to GOARCH
+// values where they differ.
+var dlArchs = map[string]string{
+	"armv6l": "arm",
+}
+
+// goarch returns the GOARCH of f.
+func (f ReleaseFile) goarch() string {
+	if goarch, ok := dlArchs[f.Arch]; ok {
+		return goarch
+	}
+	return f.Arch
+}
+
+// ToolchainModule returns the golang.org/toolchain module version holding
+// the same toolchain as f. Only archives for a specific platform have one.
+func (f ReleaseFile) ToolchainModule() (*goversion.ToolchainModule, bool) {
+	if f.Kind != "archive" || f.Version == nil || f.OS == "" || f.Arch == "" {
+		return nil, false
+	}
+	return goversion.NewToolchainModule(f.Version, f.OS, f.goarch()), true
+}
+
+// ToolchainModuleFile returns the archive in releases holding the same
+// toolchain as the golang.org/toolchain module version m.
+func ToolchainModuleFile(releases []Release, m *goversion.ToolchainModule) (ReleaseFile, bool) {
+	release, ok := findReleaseByVersion(releases, m.Version.String())
+	if !ok {
+		return ReleaseFile{}, false
+	}
+	for _, f := range release.Files {
+		fm, ok := f.ToolchainModule()
+		if ok && fm.GOOS == m.GOOS && fm.GOARCH == m.GOARCH {
+			return f, true
+		}
+	}
+	return ReleaseFile{}, false
+}
@@ -0,0 +1,466 @@
+package goversion
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Intersect returns Constraints matching versions that satisfy both c and o.
+// The result uses c's Ordering and PrereleasePolicy.
+func (c Constraints) Intersect(o *Constraints) *Constraints {
+	groups := make([][]*comparator, 0, len(c.groups)*len(o.groups))
+	for _, a := range c.groups {
+		for _, b := range o.groups {
+			group := make([]*comparator, 0, len(a)+len(b))
+			group = append(group, a...)
+			group = append(group, b...)
+			groups = append(groups, group)
+		}
+	}
+	return newConstraintsFromGroups(groups, c.options())
+}
+
+// Union returns Constraints matching versions that satisfy either c or o. The
+// result uses c's Ordering and PrereleasePolicy.
+func (c Constraints) Union(o *Constraints) *Constraints {
+	groups := make([][]*comparator, 0, len(c.groups)+len(o.groups))
+	groups = append(groups, c.groups...)
+	groups = append(groups, o.groups...)
+	return newConstraintsFromGroups(groups, c.options())
+}
+
+// IsSubset tests if every version that satisfies c also satisfies o. o is
+// evaluated with c's Ordering. PrereleaseFallback includes prereleases only
+// when no release could satisfy the constraints.
+func (c Constraints) IsSubset(o *Constraints) bool {
+	other := *o
+	other.ordering = c.ordering
+	a, b := c.versionSet(), other.versionSet()
+	return a.difference(b, c.ordering).isEmpty(c.ordering)
+}
+
+// Satisfiable tests if any version could satisfy c. PrereleaseFallback
+// includes prereleases only when no release could satisfy c.
+func (c Constraints) Satisfiable() bool {
+	return !c.versionSet().isEmpty(c.ordering)
+}
+
+// Normalized returns the versions matched by c as disjoint ranges. Release
+// ranges are written the same way as the branches of Normalize.
+// PrereleaseFallback includes prereleases only when no release could satisfy
+// c.
+func (c Constraints) Normalized() *NormalizedConstraints {
+	set := c.versionSet()
+	return &NormalizedConstraints{
+		Releases:    releaseRanges(set.releases, c.ordering),
+		Prereleases: newPrereleaseRanges(set.prereleases, c.ordering),
+	}
+}
+
+// NormalizedConstraints is the set of versions matched by Constraints
+// expressed as disjoint ranges in ascending order. Releases and prereleases
+// are kept separate because a constraint only matches prereleases when its
+// comparators name a prerelease.
+type NormalizedConstraints struct {
+	Releases    []Range
+	Prereleases []Range
+}
+
+// String returns the release ranges in go syntax like
+// ">=go1.20.0 <go1.22.0 || go1.23.1". It returns "<0.0.0" when no release
+// matches.
+func (n *NormalizedConstraints) String() string {
+	if len(n.Releases) == 0 {
+		return "<0.0.0"
+	}
+	s := make([]string, len(n.Releases))
+	for i, r := range n.Releases {
+		s[i] = r.String()
+	}
+	return strings.Join(s, " || ")
+}
+
+// Range is a contiguous range of versions. Min and Max are go versions without
+// the go prefix. An empty Min or Max is unbounded.
+type Range struct {
+	Min          string
+	MinInclusive bool
+	Max          string
+	MaxInclusive bool
+}
+
+// String returns the range in go syntax like ">=go1.20.0 <go1.22.0".
+func (r Range) String() string {
+	switch {
+	case r.Min == "" && r.Max == "":
+		return "*"
+	case r.Min == r.Max && r.MinInclusive && r.MaxInclusive:
+		return "go" + r.Min
+	}
+	var parts []string
+	if r.Min != "" {
+		op := ">"
+		if r.MinInclusive {
+			op = ">="
+		}
+		parts = append(parts, op+"go"+r.Min)
+	}
+	if r.Max != "" {
+		op := "<"
+		if r.MaxInclusive {
+			op = "<="
+		}
+		parts = append(parts, op+"go"+r.Max)
+	}
+	return strings.Join(parts, " ")
+}
+
+// newPrereleaseRanges returns intervals of prereleases as Ranges.
+func newPrereleaseRanges(intervals []interval, o Ordering) []Range {
+	if len(intervals) == 0 {
+		return nil
+	}
+	ranges := make([]Range, len(intervals))
+	for i, iv := range intervals {
+		r := Range{
+			MinInclusive: iv.lo.inclusive,
+			MaxInclusive: iv.hi.inclusive,
+		}
+		if !iv.lo.unbounded {
+			r.Min = prereleaseBoundString(iv.lo.key, o)
+		}
+		if !iv.hi.unbounded {
+			r.Max = prereleaseBoundString(iv.hi.key, o)
+		}
+		ranges[i] = r
+	}
+	return ranges
+}
+
+// prereleaseBoundString renders a bound of a prerelease range. The floor of a
+// minor version is a wildcard to reach below the minor's prereleases.
+func prereleaseBoundString(k versionKey, o Ordering) string {
+	if k.floor && k.noPatch {
+		return fmt.Sprintf("%d.%d.x", k.major, k.minor)
+	}
+	return keyString(k, o)
+}
+
+// newConstraintsFromGroups returns Constraints matching groups. The groups are
+// used as they are, and their go syntax is only kept for SemverString.
+func newConstraintsFromGroups(groups [][]*comparator, options *ConstraintsOptions) *Constraints {
+	return &Constraints{
+		original:    renderGroups(groups),
+		groups:      groups,
+		ordering:    options.Ordering,
+		prereleases: options.Prereleases,
+	}
+}
+
+// mustParseConstraints parses constraints rendered by this package.
+func mustParseConstraints(c string, options *ConstraintsOptions) *Constraints {
+	constraints, err := parseConstraints(c, options)
+	if err != nil {
+		panic(fmt.Sprintf("goversion: rendered invalid constraints %q: %v", c, err))
+	}
+	return constraints
+}
+
+func renderGroups(groups [][]*comparator) string {
+	if len(groups) == 0 {
+		return "<0.0.0"
+	}
+	branches := make([]string, len(groups))
+	for i, group := range groups {
+		s := make([]string, len(group))
+		for j, cmp := range group {
+			s[j] = comparatorSyntax(cmp.op, cmp.versionString())
+		}
+		branches[i] = strings.Join(s, " ")
+	}
+	return strings.Join(branches, " || ")
+}
+
+// comparatorSyntax joins op and version so that they parse as one comparator.
+func comparatorSyntax(op, version string) string {
+	switch op {
+	case "=":
+		return version
+	case "!=":
+		// "!=" must be separated from the version to be parsed as a go version
+		return "!= " + version
+	default:
+		return op + version
+	}
+}
+
+// versionSet is a set of versions as disjoint intervals. Prereleases are
+// tracked separately from releases.
+type versionSet struct {
+	releases    []interval
+	prereleases []interval
+}
+
+func (c Constraints) versionSet() versionSet {
+	var set versionSet
+	for _, group := range c.groups {
+		gs := c.groupSet(group)
+		set.releases = append(set.releases, gs.releases...)
+		set.prereleases = append(set.prereleases, gs.prereleases...)
+	}
+	set.releases = mergeReleaseGaps(normalizeIntervals(set.releases, c.ordering), c.ordering)
+	set.prereleases = normalizeIntervals(set.prereleases, c.ordering)
+	if c.prereleases == PrereleaseFallback && !set.hasRelease(c.ordering) {
+		c.prereleases = PrereleaseInclude
+		return c.versionSet()
+	}
+	return set
+}
+
+// groupSet returns the versions matched by every comparator in group.
+func (c Constraints) groupSet(group []*comparator) versionSet {
+	set := versionSet{
+		releases:    []interval{{unbounded, unbounded}},
+		prereleases: []interval{{unbounded, unbounded}},
+	}
+	for _, cmp := range group {
+		set.releases = intersectIntervals(set.releases, cmp.intervals(), c.ordering)
+		if c.excludesPrereleases(cmp) {
+			set.prereleases = nil
+		} else {
+			set.prereleases = intersectIntervals(set.prereleases, cmp.intervals(), c.ordering)
+		}
+	}
+	if c.prereleases == PrereleaseOnly {
+		set.releases = nil
+	}
+	return set
+}
+
+func (s versionSet) difference(o versionSet, ord Ordering) versionSet {
+	return versionSet{
+		releases:    intersectIntervals(s.releases, complementIntervals(o.releases), ord),
+		prereleases: intersectIntervals(s.prereleases, complementIntervals(o.prereleases), ord),
+	}
+}
+
+func (s versionSet) hasRelease(o Ordering) bool {
+	for _, i := range s.releases {
+		if i.hasRelease(o) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s versionSet) isEmpty(o Ordering) bool {
+	if s.hasRelease(o) {
+		return false
+	}
+	for _, i := range s.prereleases {
+		if i.hasPrerelease(o) {
+			return false
+		}
+	}
+	return true
+}
+
+// compareLo compares two lower bounds.
+func compareLo(a, b bound, o Ordering) int {
+	switch {
+	case a.unbounded && b.unbounded:
+		return 0
+	case a.unbounded:
+		return -1
+	case b.unbounded:
+		return 1
+	}
+	if c := o.compareKeys(a.key, b.key); c != 0 {
+		return c
+	}
+	switch {
+	case a.inclusive == b.inclusive:
+		return 0
+	case a.inclusive:
+		return -1
+	default:
+		return 1
+	}
+}
+
+// compareHi compares two upper bounds.
+func compareHi(a, b bound, o Ordering) int {
+	switch {
+	case a.unbounded && b.unbounded:
+		return 0
+	case a.unbounded:
+		return 1
+	case b.unbounded:
+		return -1
+	}
+	if c := o.compareKeys(a.key, b.key); c != 0 {
+		return c
+	}
+	switch {
+	case a.inclusive == b.inclusive:
+		return 0
+	case a.inclusive:
+		return 1
+	default:
+		return -1
+	}
+}
+
+// isValid returns false when the interval is empty regardless of which
+// versions exist.
+func (i interval) isValid(o Ordering) bool {
+	if i.lo.unbounded || i.hi.unbounded {
+		return true
+	}
+	c := o.compareKeys(i.lo.key, i.hi.key)
+	return c < 0 || c == 0 && i.lo.inclusive && i.hi.inclusive
+}
+
+// hasRelease tests if any release version is in the interval.
+func (i interval) hasRelease(o Ordering) bool {
+	if !i.isValid(o) {
+		return false
+	}
+	lo := i.lo
+	if lo.unbounded {
+		lo = incl(floorKey(0, 0, 0, 0))
+	}
+	return i.contains(o, nextRelease(lo, o))
+}
+
+// hasPrerelease tests if any prerelease version is in the interval. Any two
+// distinct versions have prereleases between them, so only single version
+// intervals need special handling.
+func (i interval) hasPrerelease(o Ordering) bool {
+	if !i.isValid(o) {
+		return false
+	}
+	if i.lo.unbounded || i.hi.unbounded {
+		return true
+	}
+	if o.compareKeys(i.lo.key, i.hi.key) < 0 {
+		return true
+	}
+	return i.lo.key.prerelease != "" && !i.lo.key.floor
+}
+
+// nextRelease returns the lowest release version that is within lo.
+func nextRelease(lo bound, o Ordering) versionKey {
+	k := lo.key
+	if o == GoverOrdering {
+		k = k.goverKey()
+		if k.noPatch {
+			if k.floor || k.prerelease == "" && lo.inclusive {
+				return versionKey{major: k.major, minor: k.minor, noPatch: true}
+			}
+			return versionKey{major: k.major, minor: k.minor}
+		}
+	}
+	release := versionKey{major: k.major, minor: k.minor, patch: k.patch}
+	if k.floor || k.prerelease != "" || lo.inclusive {
+		return release
+	}
+	release.patch++
+	return release
+}
+
+func intersectIntervals(a, b []interval, o Ordering) []interval {
+	var result []interval
+	for _, x := range a {
+		for _, y := range b {
+			i := interval{lo: x.lo, hi: x.hi}
+			if compareLo(y.lo, i.lo, o) > 0 {
+				i.lo = y.lo
+			}
+			if compareHi(y.hi, i.hi, o) < 0 {
+				i.hi = y.hi
+			}
+			if i.isValid(o) {
+				result = append(result, i)
+			}
+		}
+	}
+	return normalizeIntervals(result, o)
+}
+
+// complementIntervals returns the complement of normalized intervals.
+func complementIntervals(intervals []interval) []interval {
+	result := make([]interval, 0, len(intervals)+1)
+	lo := unbounded
+	for _, i := range intervals {
+		if !i.lo.unbounded {
+			result = append(result, interval{lo: lo, hi: bound{key: i.lo.key, inclusive: !i.lo.inclusive}})
+		}
+		if i.hi.unbounded {
+			return result
+		}
+		lo = bound{key: i.hi.key, inclusive: !i.hi.inclusive}
+	}
+	return append(result, interval{lo: lo, hi: unbounded})
+}
+
+// normalizeIntervals sorts intervals and merges the ones that overlap or touch.
+func normalizeIntervals(intervals []interval, o Ordering) []interval {
+	valid := make([]interval, 0, len(intervals))
+	for _, i := range intervals {
+		if i.isValid(o) {
+			valid = append(valid, i)
+		}
+	}
+	sort.SliceStable(valid, func(i, j int) bool {
+		return compareLo(valid[i].lo, valid[j].lo, o) < 0
+	})
+	var result []interval
+	for _, i := range valid {
+		if len(result) == 0 {
+			result = append(result, i)
+			continue
+		}
+		last := &result[len(result)-1]
+		if !touches(last.hi, i.lo, o) {
+			result = append(result, i)
+			continue
+		}
+		if compareHi(i.hi, last.hi, o) > 0 {
+			last.hi = i.hi
+		}
+	}
+	return result
+}
+
+// mergeReleaseGaps merges neighboring intervals when no release falls between
+// them. That way excluding a prerelease doesn't split the release ranges.
+func mergeReleaseGaps(intervals []interval, o Ordering) []interval {
+	var result []interval
+	for _, i := range intervals {
+		if len(result) > 0 {
+			last := &result[len(result)-1]
+			gap := interval{
+				lo: bound{key: last.hi.key, inclusive: !last.hi.inclusive},
+				hi: bound{key: i.lo.key, inclusive: !i.lo.inclusive},
+			}
+			if !gap.hasRelease(o) {
+				last.hi = i.hi
+				continue
+			}
+		}
+		result = append(result, i)
+	}
+	return result
+}
+
+// touches tests if an interval ending at hi overlaps or is adjacent to one
+// starting at lo.
+func touches(hi, lo bound, o Ordering) bool {
+	if hi.unbounded || lo.unbounded {
+		return true
+	}
+	c := o.compareKeys(hi.key, lo.key)
+	return c > 0 || c == 0 && (hi.inclusive || lo.inclusive)
+}
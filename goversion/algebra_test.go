@@ -0,0 +1,162 @@
+package goversion
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mustConstraints(t *testing.T, c string, ordering Ordering) *Constraints {
+	t.Helper()
+	got, err := NewConstraintsWithOptions(c, &ConstraintsOptions{Ordering: ordering})
+	require.NoError(t, err)
+	return got
+}
+
+func TestConstraints_Intersect(t *testing.T) {
+	for _, td := range []struct {
+		a, b      string
+		want      string
+		normal    string
+		matches   []string
+		unmatched []string
+	}{
+		{
+			a:         ">=1.20",
+			b:         "<1.22",
+			want:      ">=go1.20.0 <go1.22.0",
+			normal:    ">=go1.20.0 <go1.22.0",
+			matches:   []string{"go1.20", "go1.21.3"},
+			unmatched: []string{"go1.19.13", "go1.22.0"},
+		},
+		{
+			a:         "1.20.x || 1.22.x",
+			b:         ">=1.20.5 <1.23",
+			want:      "1.20.x >=go1.20.5 <go1.23.0 || 1.22.x >=go1.20.5 <go1.23.0",
+			normal:    ">=go1.20.5 <go1.21.0 || >=go1.22.0 <go1.23.0",
+			matches:   []string{"go1.20.5", "go1.22.1"},
+			unmatched: []string{"go1.20.4", "go1.21.0"},
+		},
+		{
+			a:      ">=1.21",
+			b:      "<1.20",
+			want:   ">=go1.21.0 <go1.20.0",
+			normal: "<0.0.0",
+		},
+	} {
+		t.Run(td.a+" && "+td.b, func(t *testing.T) {
+			got := mustConstraints(t, td.a, SemverOrdering).Intersect(mustConstraints(t, td.b, SemverOrdering))
+			text, err := got.MarshalText()
+			require.NoError(t, err)
+			require.Equal(t, td.want, string(text))
+			require.Equal(t, td.normal, got.Normalized().String())
+			for _, s := range td.matches {
+				assert.True(t, got.Check(mustVersion(t, s)), s)
+			}
+			for _, s := range td.unmatched {
+				assert.False(t, got.Check(mustVersion(t, s)), s)
+			}
+		})
+	}
+}
+
+func TestConstraints_algebraCompatibility(t *testing.T) {
+	for _, a := range compatibilityConstraints {
+		ca := mustConstraints(t, a, SemverOrdering)
+		for _, b := range []string{"*", ">=1.15", "1.16rc1", "<=1.9.2rc2 || 2.x"} {
+			cb := mustConstraints(t, b, SemverOrdering)
+			intersect, union := ca.Intersect(cb), ca.Union(cb)
+			for _, s := range compatibilityVersions {
+				v := mustVersion(t, s)
+				assert.Equal(t, ca.Check(v) && cb.Check(v), intersect.Check(v), "%q && %q: %s", a, b, s)
+				assert.Equal(t, ca.Check(v) || cb.Check(v), union.Check(v), "%q || %q: %s", a, b, s)
+			}
+		}
+	}
+}
+
+func TestConstraints_Union(t *testing.T) {
+	got := mustConstraints(t, "1.20.x", SemverOrdering).Union(mustConstraints(t, ">=1.21 <1.22 || != 1.16rc1", SemverOrdering))
+	text, err := got.MarshalText()
+	require.NoError(t, err)
+	require.Equal(t, "1.20.x || >=go1.21.0 <go1.22.0 || != go1.16rc1", string(text))
+	require.Equal(t, "*", got.Normalized().String())
+
+	got = mustConstraints(t, "1.20.x", SemverOrdering).Union(mustConstraints(t, "1.21.x", SemverOrdering))
+	require.Equal(t, ">=go1.20.0 <go1.22.0", got.Normalized().String())
+	require.Empty(t, got.Normalized().Prereleases)
+}
+
+func TestConstraints_IsSubset(t *testing.T) {
+	for _, td := range []struct {
+		a, b     string
+		ordering Ordering
+		want     bool
+	}{
+		{a: "1.21.x", b: ">=1.20", want: true},
+		{a: ">=1.20", b: "1.21.x", want: false},
+		{a: "~1.20.3", b: "1.20.x", want: true},
+		{a: "1.20.x || 1.21.x", b: ">=1.20 <1.22", want: true},
+		{a: ">=1.20 <1.22", b: "1.20.x || 1.21.x", want: true},
+		{a: ">1.20.3 <1.20.4", b: "1.21.x", want: true},
+		{a: ">=1.21rc1 <1.22", b: ">=1.21", want: true},
+		{a: ">=1.21rc1 <1.22rc1", b: ">=1.21", want: false},
+		{a: ">=1.21rc1 <1.22", b: ">=1.21rc1", want: true},
+		{a: "1.21.x", b: ">=1.21.0", want: true},
+		{a: "1.21.x", b: ">=1.21.0", ordering: GoverOrdering, want: false},
+		{a: "<1.21.0", b: "<=1.21", ordering: GoverOrdering, want: true},
+		{a: "<1.21.0", b: "<=1.21", want: true},
+	} {
+		t.Run(td.a+" in "+td.b, func(t *testing.T) {
+			a := mustConstraints(t, td.a, td.ordering)
+			b := mustConstraints(t, td.b, td.ordering)
+			require.Equal(t, td.want, a.IsSubset(b))
+		})
+	}
+}
+
+func TestConstraints_Satisfiable(t *testing.T) {
+	for _, td := range []struct {
+		c        string
+		ordering Ordering
+		want     bool
+	}{
+		{c: "*", want: true},
+		{c: ">=1.21 <1.20", want: false},
+		{c: ">1.20.3 <1.20.4", want: false},
+		{c: ">1.20.3 <1.20.4rc1", want: false},
+		{c: ">1.20.3rc1 <1.20.4rc1", want: true},
+		{c: ">=1.21 <=1.21", want: true},
+		{c: ">1.21 <1.21.0", want: false},
+		{c: ">1.21 <1.21.0", ordering: GoverOrdering, want: false},
+		{c: ">=1.21 <1.21.0", ordering: GoverOrdering, want: true},
+		{c: ">1.21rc1 <1.21.0rc1", ordering: GoverOrdering, want: true},
+		{c: "<0.0.0", want: false},
+		{c: "1.21.x != 1.21.x", want: false},
+	} {
+		t.Run(td.c, func(t *testing.T) {
+			require.Equal(t, td.want, mustConstraints(t, td.c, td.ordering).Satisfiable())
+		})
+	}
+}
+
+func TestConstraints_Normalized(t *testing.T) {
+	got := mustConstraints(t, ">=1.21rc1 <1.22 || 1.23.x", GoverOrdering).Normalized()
+	require.Equal(t, &NormalizedConstraints{
+		Releases: []Range{
+			{Min: "1.21.0", MinInclusive: true, Max: "1.22"},
+			{Min: "1.23", MinInclusive: true, Max: "1.24"},
+		},
+	}, got)
+	require.Equal(t, ">=go1.21.0 <go1.22 || >=go1.23 <go1.24", got.String())
+
+	got = mustConstraints(t, ">=1.21rc1 <1.22rc1", GoverOrdering).Normalized()
+	require.Equal(t, []Range{{Min: "1.21rc1", MinInclusive: true, Max: "1.22rc1"}}, got.Prereleases)
+
+	got = mustConstraints(t, "1.21.3", SemverOrdering).Normalized()
+	require.Equal(t, "go1.21.3", got.String())
+
+	got = mustConstraints(t, "<=1.15", SemverOrdering).Normalized()
+	require.Equal(t, "<go1.15.1", got.String())
+}
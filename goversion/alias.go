@@ -0,0 +1,140 @@
+package goversion
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Aliases are named constraints that are resolved against a set of candidate
+// versions with ResolveConstraints.
+const (
+	// AliasStable matches the newest stable release.
+	AliasStable = "stable"
+
+	// AliasOldStable matches the newest stable release of the minor version
+	// before AliasStable's.
+	AliasOldStable = "oldstable"
+
+	// AliasLatest matches the newest release including prereleases.
+	AliasLatest = "latest"
+
+	// AliasSupported matches the stable releases of the two newest minor
+	// versions. Under the Go release policy those are the supported releases.
+	AliasSupported = "supported"
+)
+
+// ErrNoAliasMatch is returned when an alias doesn't match any candidate version
+var ErrNoAliasMatch = fmt.Errorf("alias does not match any candidate versions")
+
+// IsAlias returns true if c is one of the named alias constraints.
+func IsAlias(c string) bool {
+	switch strings.TrimSpace(c) {
+	case AliasStable, AliasOldStable, AliasLatest, AliasSupported:
+		return true
+	default:
+		return false
+	}
+}
+
+// ResolveConstraints is like NewConstraintsWithOptions but also accepts the
+// named aliases. Aliases are resolved against candidates and return
+// Constraints matching exactly the versions the alias refers to. Language
+// versions like go1.21 that were never released under that name and devel
+// builds are not considered. Constraints resolved from an alias use
+// GoverOrdering regardless of options because only GoverOrdering tells
+// go1.21 from go1.21.0, so language versions are not matched either.
+func ResolveConstraints(c string, candidates []*Version, options *ConstraintsOptions) (*Constraints, error) {
+	if !IsAlias(c) {
+		return NewConstraintsWithOptions(c, options)
+	}
+	if options == nil {
+		options = new(ConstraintsOptions)
+	}
+	alias := strings.TrimSpace(c)
+	matches := resolveAlias(alias, aliasReleases(candidates, options.Ordering))
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNoAliasMatch, alias)
+	}
+	resolved := *options
+	resolved.Ordering = GoverOrdering
+	return NewConstraintsWithOptions(strings.Join(matches, " || "), &resolved)
+}
+
+// aliasReleases returns the prereleases and releases in candidates sorted
+// newest first.
+func aliasReleases(candidates []*Version, ordering Ordering) []*Version {
+	releases := make([]*Version, 0, len(candidates))
+	for _, v := range candidates {
+		if v.Lang().Contains(v) && !v.IsDevel() {
+			releases = append(releases, v)
+		}
+	}
+	sort.SliceStable(releases, func(i, j int) bool {
+		return ordering.Less(releases[j], releases[i])
+	})
+	return releases
+}
+
+// resolveAlias returns the constraints alias resolves to against releases
+// sorted newest first.
+func resolveAlias(alias string, releases []*Version) []string {
+	var matches []string
+	switch alias {
+	case AliasLatest:
+		if len(releases) > 0 {
+			matches = append(matches, exactConstraint(releases[0]))
+		}
+	case AliasStable:
+		if stable := newestStable(releases, nil); stable != nil {
+			matches = append(matches, exactConstraint(stable))
+		}
+	case AliasOldStable:
+		if stable := newestStable(releases, nil); stable != nil {
+			lang := stable.Lang()
+			if old := newestStable(releases, &lang); old != nil {
+				matches = append(matches, exactConstraint(old))
+			}
+		}
+	case AliasSupported:
+		var below *Lang
+		for i := 0; i < 2; i++ {
+			v := newestStable(releases, below)
+			if v == nil {
+				break
+			}
+			lang := v.Lang()
+			below = &lang
+			matches = append(matches, langReleasesConstraint(lang))
+		}
+	}
+	return matches
+}
+
+// newestStable returns the first stable version in sorted that belongs to a
+// language version before below. A nil below means any language version.
+func newestStable(sorted []*Version, below *Lang) *Version {
+	for _, v := range sorted {
+		if !v.IsStable() {
+			continue
+		}
+		if below != nil && v.Lang().Compare(*below) >= 0 {
+			continue
+		}
+		return v
+	}
+	return nil
+}
+
+// exactConstraint returns a constraint matching only v.
+func exactConstraint(v *Version) string {
+	return "=" + strings.TrimPrefix(v.String(), "go")
+}
+
+// langReleasesConstraint returns a constraint matching the releases of l like
+// ">=1.21.0 <1.22". Unlike "1.21.x" it doesn't match the language version
+// go1.21 under GoverOrdering.
+func langReleasesConstraint(l Lang) string {
+	first := strings.TrimPrefix(l.FirstRelease().String(), "go")
+	return fmt.Sprintf(">=%s <%d.%d", first, l.major, l.minor+1)
+}
@@ -0,0 +1,69 @@
+package goversion
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveConstraints(t *testing.T) {
+	var candidates []*Version
+	for _, s := range []string{
+		"go1.20", "go1.20.1", "go1.20.14", "go1.21", "go1.21rc2", "go1.21.0", "go1.21.7", "go1.22rc1", "go1.22.0",
+		"go1.22.1", "go1.23rc1",
+	} {
+		candidates = append(candidates, mustVersion(t, s))
+	}
+	for _, td := range []struct {
+		constraint string
+		ordering   Ordering
+		want       []string
+	}{
+		{constraint: "stable", want: []string{"go1.22.1"}},
+		{constraint: " stable ", want: []string{"go1.22.1"}},
+		{constraint: "oldstable", want: []string{"go1.21.7"}},
+		{constraint: "latest", want: []string{"go1.23rc1"}},
+		{constraint: "supported", want: []string{"go1.21.0", "go1.21.7", "go1.22.0", "go1.22.1"}},
+		{constraint: "supported", ordering: GoverOrdering, want: []string{"go1.21.0", "go1.21.7", "go1.22.0", "go1.22.1"}},
+		{constraint: "1.20.x", want: []string{"go1.20", "go1.20.1", "go1.20.14"}},
+	} {
+		t.Run(td.constraint+" "+td.ordering.String(), func(t *testing.T) {
+			c, err := ResolveConstraints(td.constraint, candidates, &ConstraintsOptions{Ordering: td.ordering})
+			require.NoError(t, err)
+			var got []string
+			for _, v := range c.FilterVersions(candidates) {
+				got = append(got, v.String())
+			}
+			assert.ElementsMatch(t, td.want, got)
+		})
+	}
+
+	t.Run("language version", func(t *testing.T) {
+		versions := []*Version{mustVersion(t, "go1.21"), mustVersion(t, "go1.21.0")}
+		c, err := ResolveConstraints("stable", versions, nil)
+		require.NoError(t, err)
+		require.Equal(t, GoverOrdering, c.Ordering())
+		require.Equal(t, versions[1:], c.FilterVersions(versions))
+	})
+
+	t.Run("no candidates", func(t *testing.T) {
+		_, err := ResolveConstraints("stable", nil, nil)
+		require.True(t, errors.Is(err, ErrNoAliasMatch))
+	})
+
+	t.Run("no oldstable", func(t *testing.T) {
+		_, err := ResolveConstraints("oldstable", []*Version{mustVersion(t, "go1.22.0")}, nil)
+		require.True(t, errors.Is(err, ErrNoAliasMatch))
+	})
+}
+
+func TestIsAlias(t *testing.T) {
+	assert.True(t, IsAlias("stable"))
+	assert.True(t, IsAlias("oldstable"))
+	assert.True(t, IsAlias("latest"))
+	assert.True(t, IsAlias("supported"))
+	assert.False(t, IsAlias("1.x"))
+	assert.False(t, IsAlias("stable || 1.x"))
+}
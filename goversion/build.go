@@ -0,0 +1,200 @@
+package goversion
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// BuildVersion is the version of a go toolchain or binary along with the
+// details that runtime.Version, "go version" and $GOROOT/VERSION report.
+type BuildVersion struct {
+	// Version is nil for old devel builds like "devel +abc123" that don't
+	// report a version.
+	Version *Version
+
+	// Suffix is the custom toolchain suffix like "bigcorp" in
+	// "go1.21.0-bigcorp". Version doesn't include it.
+	Suffix string
+
+	// GOOS and GOARCH are only set when parsed from "go version" output.
+	GOOS   string
+	GOARCH string
+
+	// Experiments are the GOEXPERIMENT flags from a suffix like
+	// "X:nocoverageredesign".
+	Experiments []string
+
+	// Commit is the commit a devel build was made from.
+	Commit string
+
+	// Time is the commit time of a devel build or the time line of a
+	// $GOROOT/VERSION file. It is zero when unknown.
+	Time time.Time
+}
+
+// develTimeLayout is the format of the commit time in devel versions.
+const develTimeLayout = "Mon Jan 2 15:04:05 2006 -0700"
+
+// ParseBuildVersion parses the version reported by runtime.Version like
+// "go1.22.1 X:nocoverageredesign" or
+// "devel go1.23-abc123 Tue Apr 2 10:49:32 2024 +0000". It also accepts the
+// output of "go version" like "go version go1.22.1 linux/amd64" and the first
+// line of "go version -m" like "./bin/app: go1.22.1". Custom toolchains like
+// "go1.21.0-bigcorp" keep their suffix in Suffix. The error is a *ParseError.
+func ParseBuildVersion(s string) (*BuildVersion, error) {
+	b, ok := parseBuildVersion(s)
+	if !ok {
+		return nil, newVersionError(s)
+	}
+	return b, nil
+}
+
+func parseBuildVersion(s string) (*BuildVersion, bool) {
+	b := new(BuildVersion)
+	fields := b.parseFields(buildVersionLine(s))
+	if len(fields) == 0 {
+		return nil, false
+	}
+	ok := len(fields) == 1 && b.parseRelease(fields[0])
+	if fields[0] == "devel" {
+		ok = b.parseDevel(fields[1:])
+	}
+	if !ok {
+		return nil, false
+	}
+	return b, true
+}
+
+// buildVersionLine returns the first line of s without the "go version " or
+// "path: " prefix.
+func buildVersionLine(s string) string {
+	line := strings.TrimSpace(s)
+	if i := strings.IndexByte(line, '\n'); i != -1 {
+		line = strings.TrimSpace(line[:i])
+	}
+	switch {
+	case strings.HasPrefix(line, "go version "):
+		return strings.TrimPrefix(line, "go version ")
+	case strings.Contains(line, ": "):
+		return line[strings.LastIndex(line, ": ")+2:]
+	}
+	return line
+}
+
+// parseFields sets b's experiments and platform from the fields of line and
+// returns the remaining fields.
+func (b *BuildVersion) parseFields(line string) []string {
+	var fields []string
+	for _, field := range strings.Fields(line) {
+		switch {
+		case strings.HasPrefix(field, "X:"):
+			b.Experiments = append(b.Experiments, strings.Split(field[2:], ",")...)
+		case strings.Count(field, "/") == 1 && !strings.HasPrefix(field, "/"):
+			b.GOOS, b.GOARCH = splitPlatform(field)
+		default:
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// parseRelease sets b's version from a toolchain name like "go1.22.1" or
+// "go1.21.0-bigcorp".
+func (b *BuildVersion) parseRelease(name string) bool {
+	name, suffix, hasSuffix := strings.Cut(name, "-")
+	if hasSuffix && suffix == "" {
+		return false
+	}
+	v, ok := parseVersion(name)
+	if !ok {
+		return false
+	}
+	b.Version, b.Suffix = &v, suffix
+	return true
+}
+
+// parseDevel sets b's version, commit and time from the fields following
+// "devel" like "go1.23-abc123 Tue Apr 2 10:49:32 2024 +0000".
+func (b *BuildVersion) parseDevel(fields []string) bool {
+	if len(fields) == 0 {
+		return false
+	}
+	switch {
+	case strings.HasPrefix(fields[0], "+"):
+		b.Commit = fields[0][1:]
+	case strings.HasPrefix(fields[0], "go"):
+		name, commit, _ := strings.Cut(fields[0], "-")
+		v, ok := parseVersion("devel " + name)
+		if !ok {
+			return false
+		}
+		b.Version, b.Commit = &v, commit
+	default:
+		return false
+	}
+	if len(fields) > 1 {
+		t, err := time.Parse(develTimeLayout, strings.Join(fields[1:], " "))
+		if err != nil {
+			return false
+		}
+		b.Time = t
+	}
+	return true
+}
+
+func splitPlatform(s string) (goos, goarch string) {
+	i := strings.IndexByte(s, '/')
+	return s[:i], s[i+1:]
+}
+
+// ReadVersionFile reads a $GOROOT/VERSION file.
+func ReadVersionFile(filename string) (*BuildVersion, error) {
+	data, err := os.ReadFile(filename) //nolint:gosec // reading user-provided files is the point
+	if err != nil {
+		return nil, err
+	}
+	b, err := ParseVersionFile(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return b, nil
+}
+
+// ParseVersionFile parses the contents of a $GOROOT/VERSION file. The first
+// line is the version, and a later line like "time 2024-02-29T18:18:16Z" sets
+// Time.
+func ParseVersionFile(data []byte) (*BuildVersion, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	var b *BuildVersion
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		if lineNum == 1 {
+			var err error
+			b, err = ParseBuildVersion(line)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNum, err)
+			}
+			continue
+		}
+		if !strings.HasPrefix(line, "time ") {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, strings.TrimPrefix(line, "time "))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid time: %w", lineNum, err)
+		}
+		b.Time = t
+	}
+	err := scanner.Err()
+	if err != nil {
+		return nil, err
+	}
+	if b == nil {
+		return nil, newVersionError("")
+	}
+	return b, nil
+}
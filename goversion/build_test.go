@@ -0,0 +1,154 @@
+package goversion
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBuildVersion(t *testing.T) {
+	for _, td := range []struct {
+		input   string
+		version string
+		want    BuildVersion
+		wantErr bool
+	}{
+		{
+			input:   "go1.22.1",
+			version: "go1.22.1",
+		},
+		{
+			input:   "go1.22.1 X:nocoverageredesign",
+			version: "go1.22.1",
+			want:    BuildVersion{Experiments: []string{"nocoverageredesign"}},
+		},
+		{
+			input:   "go version go1.22.1 X:loopvar,rangefunc linux/amd64\n",
+			version: "go1.22.1",
+			want: BuildVersion{
+				GOOS:        "linux",
+				GOARCH:      "amd64",
+				Experiments: []string{"loopvar", "rangefunc"},
+			},
+		},
+		{
+			input:   "./bin/app: go1.21rc2\n\tpath\texample.com/app\n",
+			version: "go1.21rc2",
+		},
+		{
+			input:   "devel go1.23-3ea5d12a5c Tue Apr 2 10:49:32 2024 +0000",
+			version: "devel go1.23",
+			want: BuildVersion{
+				Commit: "3ea5d12a5c",
+				Time:   time.Date(2024, 4, 2, 10, 49, 32, 0, time.UTC),
+			},
+		},
+		{
+			input:   "go version devel go1.23-3ea5d12a5c Tue Apr 2 10:49:32 2024 +0000 X:rangefunc darwin/arm64",
+			version: "devel go1.23",
+			want: BuildVersion{
+				GOOS:        "darwin",
+				GOARCH:      "arm64",
+				Experiments: []string{"rangefunc"},
+				Commit:      "3ea5d12a5c",
+				Time:        time.Date(2024, 4, 2, 10, 49, 32, 0, time.UTC),
+			},
+		},
+		{
+			input: "devel +b0beeb1 Wed Jan 13 17:59:53 2021 +0000",
+			want: BuildVersion{
+				Commit: "b0beeb1",
+				Time:   time.Date(2021, 1, 13, 17, 59, 53, 0, time.UTC),
+			},
+		},
+		{
+			input:   "go1.21.0-bigcorp",
+			version: "go1.21.0",
+			want:    BuildVersion{Suffix: "bigcorp"},
+		},
+		{
+			input:   "go version go1.21.0-bigcorp X:boringcrypto linux/amd64",
+			version: "go1.21.0",
+			want: BuildVersion{
+				Suffix:      "bigcorp",
+				GOOS:        "linux",
+				GOARCH:      "amd64",
+				Experiments: []string{"boringcrypto"},
+			},
+		},
+		{input: "", wantErr: true},
+		{input: "go1.21.0-", wantErr: true},
+		{input: "devel", wantErr: true},
+		{input: "devel go1.23-abc yesterday", wantErr: true},
+		{input: "go version go1.18 gccgo (GCC) 12.2.0 linux/amd64", wantErr: true},
+		{input: "go1.22.1.1", wantErr: true},
+	} {
+		t.Run(td.input, func(t *testing.T) {
+			got, err := ParseBuildVersion(td.input)
+			if td.wantErr {
+				require.True(t, errors.Is(err, ErrInvalidGoVersion))
+				return
+			}
+			require.NoError(t, err)
+			if td.version == "" {
+				require.Nil(t, got.Version)
+			} else {
+				require.Equal(t, td.version, got.Version.String())
+			}
+			got.Version = nil
+			assert.True(t, td.want.Time.Equal(got.Time))
+			got.Time, td.want.Time = time.Time{}, time.Time{}
+			assert.Equal(t, &td.want, got)
+		})
+	}
+}
+
+func TestParseVersionFile(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "VERSION")
+	err := os.WriteFile(filename, []byte("go1.22.1\ntime 2024-02-29T18:18:16Z\n"), 0o600)
+	require.NoError(t, err)
+	got, err := ReadVersionFile(filename)
+	require.NoError(t, err)
+	require.Equal(t, "go1.22.1", got.Version.String())
+	require.Equal(t, time.Date(2024, 2, 29, 18, 18, 16, 0, time.UTC), got.Time)
+
+	_, err = ParseVersionFile([]byte("go1.22.1\ntime yesterday\n"))
+	require.EqualError(t, err, `line 2: invalid time: parsing time "yesterday" as "2006-01-02T15:04:05Z07:00": cannot parse "yesterday" as "2006"`)
+	_, err = ParseVersionFile(nil)
+	require.True(t, errors.Is(err, ErrInvalidGoVersion))
+}
+
+func TestVersion_devel(t *testing.T) {
+	devel := mustVersion(t, "devel go1.23")
+	require.True(t, devel.IsDevel())
+	require.False(t, devel.IsStable())
+	require.False(t, devel.IsLang())
+	require.Equal(t, "devel go1.23", devel.String())
+
+	for _, o := range []Ordering{SemverOrdering, GoverOrdering} {
+		assert.True(t, o.Less(mustVersion(t, "go1.22.5"), devel), o)
+		assert.True(t, o.Less(devel, mustVersion(t, "go1.23rc1")), o)
+		assert.True(t, o.Less(devel, mustVersion(t, "go1.23.0")), o)
+		assert.Zero(t, o.Compare(devel, mustVersion(t, "devel go1.23")), o)
+	}
+	assert.True(t, GoverOrdering.Less(devel, mustVersion(t, "go1.23")))
+	assert.True(t, devel.LessThan(mustVersion(t, "go1.23")))
+
+	c, err := NewConstraints(">=1.22")
+	require.NoError(t, err)
+	assert.False(t, c.Check(devel))
+	c, err = NewConstraintsWithOptions(">=1.22", &ConstraintsOptions{Prereleases: PrereleaseInclude})
+	require.NoError(t, err)
+	assert.True(t, c.Check(devel))
+
+	_, err = NewVersion("devel go1.23rc1")
+	require.Error(t, err)
+	_, err = NewVersion("go version go1.22.1 linux/amd64")
+	require.EqualError(t, err, `invalid go version "go version go1.22.1 linux/amd64": unexpected " version go1.22.1 linux/amd64" at position 2: this looks like go version output for go1.22.1; use ParseBuildVersion`)
+}
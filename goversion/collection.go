@@ -1,5 +1,7 @@
 package goversion
 
+import "slices"
+
 // Collection is a collection of Version instances and implements the sort
 // interface. See the sort package for more details.
 // https://golang.org/pkg/sort/
@@ -22,3 +24,114 @@ func (c Collection) Less(i, j int) bool {
 func (c Collection) Swap(i, j int) {
 	c[i], c[j] = c[j], c[i]
 }
+
+// Compare returns -1, 0 or 1 depending on whether a is less than, equal to or
+// greater than b using SemverOrdering. It can be passed to slices.SortFunc.
+func Compare(a, b *Version) int {
+	return a.Compare(b)
+}
+
+// Latest returns the newest version in c or nil if c is empty. When versions
+// are equal the first one wins.
+func (c Collection) Latest() *Version {
+	var result *Version
+	for _, v := range c {
+		if result == nil || v.GreaterThan(result) {
+			result = v
+		}
+	}
+	return result
+}
+
+// Oldest returns the oldest version in c or nil if c is empty. When versions
+// are equal the first one wins.
+func (c Collection) Oldest() *Version {
+	var result *Version
+	for _, v := range c {
+		if result == nil || v.LessThan(result) {
+			result = v
+		}
+	}
+	return result
+}
+
+// LatestStable returns the newest stable version in c or nil if there is none.
+// Language versions like go1.21 that were never released under that name are
+// not stable releases.
+func (c Collection) LatestStable() *Version {
+	var result *Version
+	for _, v := range c {
+		if !v.IsStable() || !v.Lang().Contains(v) {
+			continue
+		}
+		if result == nil || v.GreaterThan(result) {
+			result = v
+		}
+	}
+	return result
+}
+
+// Contains tests if c contains a version identical to v. Versions are
+// identical when they have the same String, so go1.21 and go1.21.0 are
+// different versions here even though they are Equal.
+func (c Collection) Contains(v *Version) bool {
+	for _, o := range c {
+		if identical(o, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// Dedup returns the versions in c with identical versions removed. The first
+// of each identical version is kept and the order of c is preserved.
+func (c Collection) Dedup() Collection {
+	result := make(Collection, 0, len(c))
+	seen := make(map[Version]bool, len(c))
+	for _, v := range c {
+		if seen[*v] {
+			continue
+		}
+		seen[*v] = true
+		result = append(result, v)
+	}
+	return result
+}
+
+// Filter returns the versions in c that satisfy constraints.
+func (c Collection) Filter(constraints *Constraints) Collection {
+	return constraints.FilterVersions(c)
+}
+
+// GroupByMinor groups the versions in c by their language version. Each group
+// is in the order the versions appear in c.
+func (c Collection) GroupByMinor() map[Lang]Collection {
+	result := map[Lang]Collection{}
+	for _, v := range c {
+		result[v.Lang()] = append(result[v.Lang()], v)
+	}
+	return result
+}
+
+// LatestPatchPerMinor returns the newest stable version of each language
+// version in c in ascending order. Language versions without a stable version
+// in c are left out.
+func (c Collection) LatestPatchPerMinor() Collection {
+	groups := c.GroupByMinor()
+	result := make(Collection, 0, len(groups))
+	for _, group := range groups {
+		if v := group.LatestStable(); v != nil {
+			result = append(result, v)
+		}
+	}
+	slices.SortFunc(result, Compare)
+	return result
+}
+
+// identical tests if a and b have the same String.
+func identical(a, b *Version) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
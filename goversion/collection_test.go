@@ -0,0 +1,76 @@
+package goversion
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mustCollection(t *testing.T, versions ...string) Collection {
+	t.Helper()
+	result := make(Collection, len(versions))
+	for i, s := range versions {
+		result[i] = mustVersion(t, s)
+	}
+	return result
+}
+
+func collectionStrings(c Collection) []string {
+	result := make([]string, len(c))
+	for i, v := range c {
+		result[i] = v.String()
+	}
+	return result
+}
+
+func TestCompare(t *testing.T) {
+	c := mustCollection(t, "go1.21.0", "go1.9", "go1.21rc1", "go1.20.3", "go1.10")
+	slices.SortFunc(c, Compare)
+	assert.Equal(t, []string{"go1.9", "go1.10", "go1.20.3", "go1.21rc1", "go1.21.0"}, collectionStrings(c))
+}
+
+func TestCollection_queries(t *testing.T) {
+	c := mustCollection(t, "go1.20.2", "go1.22rc1", "go1.21.3", "go1.19", "go1.21.0", "go1.20.2")
+	assert.Equal(t, "go1.22rc1", versionString(c.Latest()))
+	assert.Equal(t, "go1.19", versionString(c.Oldest()))
+	assert.Equal(t, "go1.21.3", versionString(c.LatestStable()))
+	assert.True(t, c.Contains(mustVersion(t, "go1.21.0")))
+	assert.False(t, c.Contains(mustVersion(t, "go1.21")))
+	assert.False(t, c.Contains(nil))
+
+	var empty Collection
+	assert.Nil(t, empty.Latest())
+	assert.Nil(t, empty.Oldest())
+	assert.Nil(t, empty.LatestStable())
+	assert.Nil(t, mustCollection(t, "go1.22rc1").LatestStable())
+	assert.Equal(t, "go1.21.0", versionString(mustCollection(t, "go1.20.14", "go1.21", "go1.21.0").LatestStable()))
+	assert.Equal(t, "go1.21.0", versionString(mustCollection(t, "go1.21.0", "go1.21").LatestStable()))
+	assert.Equal(t, "go1.20.14", versionString(mustCollection(t, "go1.20.14", "go1.21").LatestStable()))
+}
+
+func TestCollection_Dedup(t *testing.T) {
+	c := mustCollection(t, "go1.21.0", "go1.20", "go1.21", "go1.21.0", "go1.20")
+	assert.Equal(t, []string{"go1.21.0", "go1.20", "go1.21"}, collectionStrings(c.Dedup()))
+}
+
+func TestCollection_Filter(t *testing.T) {
+	c := mustCollection(t, "go1.19", "go1.20.2", "go1.21rc1", "go1.21.3")
+	constraints, err := NewConstraints(">= 1.20")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"go1.20.2", "go1.21.3"}, collectionStrings(c.Filter(constraints)))
+}
+
+func TestCollection_GroupByMinor(t *testing.T) {
+	c := mustCollection(t, "go1.21.3", "go1.20", "go1.21rc1", "go1.20.1", "go1.21.0")
+	groups := c.GroupByMinor()
+	require.Len(t, groups, 2)
+	assert.Equal(t, []string{"go1.21.3", "go1.21rc1", "go1.21.0"}, collectionStrings(groups[Lang{major: 1, minor: 21}]))
+	assert.Equal(t, []string{"go1.20", "go1.20.1"}, collectionStrings(groups[Lang{major: 1, minor: 20}]))
+}
+
+func TestCollection_LatestPatchPerMinor(t *testing.T) {
+	c := mustCollection(t, "go1.21.3", "go1.20", "go1.22rc1", "go1.20.1", "go1.21.0", "go1.19.13")
+	assert.Equal(t, []string{"go1.19.13", "go1.20.1", "go1.21.3"}, collectionStrings(c.LatestPatchPerMinor()))
+}
@@ -0,0 +1,411 @@
+package goversion
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// The comparator grammar mirrors github.com/Masterminds/semver/v3 so that
+// constraints are interpreted the same way they were when checking was
+// delegated to that package.
+
+const semverConstraintOps = `=||!=|>|<|>=|=>|<=|=<|~|~>|\^`
+
+const semverConstraintVersion = `v?([0-9|x|X|\*]+)(\.[0-9|x|X|\*]+)?(\.[0-9|x|X|\*]+)?` +
+	`(-([0-9A-Za-z\-]+(\.[0-9A-Za-z\-]+)*))?` +
+	`(\+([0-9A-Za-z\-]+(\.[0-9A-Za-z\-]+)*))?`
+
+var (
+	findComparatorRegexp *regexp.Regexp
+	hyphenRangeRegexp    *regexp.Regexp
+)
+
+var initComparatorRegexpOnce sync.Once
+
+func initComparatorRegexp() {
+	initComparatorRegexpOnce.Do(func() {
+		findComparatorRegexp = regexp.MustCompile(fmt.Sprintf(`(%s)\s*(%s)`, semverConstraintOps, semverConstraintVersion))
+		hyphenRangeRegexp = regexp.MustCompile(fmt.Sprintf(`\s*(%s)\s+-\s+(%s)\s*`, semverConstraintVersion, semverConstraintVersion))
+	})
+}
+
+// comparator is a single comparison such as ">=1.15.0" or "1.16.x".
+type comparator struct {
+	op   string // one of = != > < >= <= ~ ^
+	orig string // the version as written in the semver range
+
+	major, minor, patch uint64
+	prerelease          string
+
+	// noPatch is set when the go version in the constraint had no patch. It
+	// only matters for GoverOrdering.
+	noPatch bool
+
+	dirty, minorDirty, patchDirty bool
+}
+
+// String returns the comparator in go syntax like ">=1.21rc1" or "1.20.x".
+func (c *comparator) String() string {
+	op := c.op
+	if op == "=" {
+		op = ""
+	}
+	return op + c.versionString()
+}
+
+func (c *comparator) versionString() string {
+	switch {
+	case c.minorDirty:
+		return fmt.Sprintf("%d.x", c.major)
+	case c.patchDirty:
+		return fmt.Sprintf("%d.%d.x", c.major, c.minor)
+	case c.dirty:
+		return "*"
+	}
+	s := fmt.Sprintf("%d.%d", c.major, c.minor)
+	if !c.noPatch {
+		s += fmt.Sprintf(".%d", c.patch)
+	}
+	return s + c.prerelease
+}
+
+// bound is one end of an interval. A bound with unbounded set extends to
+// infinity in its direction.
+type bound struct {
+	key       versionKey
+	inclusive bool
+	unbounded bool
+}
+
+// interval is a contiguous range of versions.
+type interval struct {
+	lo, hi bound
+}
+
+func (i interval) contains(o Ordering, k versionKey) bool {
+	if !i.lo.unbounded {
+		c := o.compareKeys(k, i.lo.key)
+		if c < 0 || c == 0 && !i.lo.inclusive {
+			return false
+		}
+	}
+	if !i.hi.unbounded {
+		c := o.compareKeys(k, i.hi.key)
+		if c > 0 || c == 0 && !i.hi.inclusive {
+			return false
+		}
+	}
+	return true
+}
+
+var unbounded = bound{unbounded: true}
+
+func floorKey(major, minor, patch uint64, level int) versionKey {
+	switch level {
+	case 0:
+		return versionKey{major: major, noPatch: true, floor: true}
+	case 1:
+		return versionKey{major: major, minor: minor, noPatch: true, floor: true}
+	default:
+		return versionKey{major: major, minor: minor, patch: patch, floor: true}
+	}
+}
+
+func incl(k versionKey) bound { return bound{key: k, inclusive: true} }
+
+func excl(k versionKey) bound { return bound{key: k} }
+
+// key returns the version the comparator compares against.
+func (c *comparator) key() versionKey {
+	return versionKey{
+		major:      c.major,
+		minor:      c.minor,
+		patch:      c.patch,
+		noPatch:    c.noPatch,
+		prerelease: c.prerelease,
+	}
+}
+
+// lowest returns the lowest version matched by the comparator's version. For
+// wildcards that is the floor of the wildcard's family.
+func (c *comparator) lowest() versionKey {
+	switch {
+	case c.minorDirty:
+		return floorKey(c.major, 0, 0, 0)
+	case c.patchDirty:
+		return floorKey(c.major, c.minor, 0, 1)
+	case c.dirty:
+		return floorKey(0, 0, 0, 0)
+	default:
+		return c.key()
+	}
+}
+
+func (c *comparator) nextMajor() versionKey {
+	return floorKey(c.major+1, 0, 0, 0)
+}
+
+func (c *comparator) nextMinor() versionKey {
+	return floorKey(c.major, c.minor+1, 0, 1)
+}
+
+func (c *comparator) isZero() bool {
+	return c.major == 0 && c.minor == 0 && c.patch == 0
+}
+
+// intervals returns the ranges of versions that satisfy the comparator
+// without regard to the prerelease rule.
+func (c *comparator) intervals() []interval {
+	switch c.op {
+	case "=":
+		if c.dirty {
+			return c.tildeIntervals()
+		}
+		return []interval{{incl(c.lowest()), incl(c.lowest())}}
+	case "!=":
+		return c.notEqualIntervals()
+	case ">", "<", ">=", "<=":
+		return c.inequalityIntervals()
+	case "~":
+		return c.tildeIntervals()
+	case "^":
+		return c.caretIntervals()
+	}
+	return nil
+}
+
+// wildcardEnd returns the floor of the family after a wildcard like 1.x or
+// 1.2.x. It is false when the comparator has no major or minor wildcard.
+func (c *comparator) wildcardEnd() (versionKey, bool) {
+	switch {
+	case c.minorDirty:
+		return c.nextMajor(), true
+	case c.patchDirty:
+		return c.nextMinor(), true
+	default:
+		return versionKey{}, false
+	}
+}
+
+func (c *comparator) notEqualIntervals() []interval {
+	if end, ok := c.wildcardEnd(); ok {
+		return []interval{{unbounded, excl(c.lowest())}, {incl(end), unbounded}}
+	}
+	return []interval{{unbounded, excl(c.key())}, {excl(c.key()), unbounded}}
+}
+
+func (c *comparator) inequalityIntervals() []interval {
+	lo := c.lowest()
+	end, wildcard := c.wildcardEnd()
+	switch c.op {
+	case ">":
+		if wildcard {
+			return []interval{{incl(end), unbounded}}
+		}
+		return []interval{{excl(c.key()), unbounded}}
+	case "<":
+		return []interval{{unbounded, excl(lo)}}
+	case ">=":
+		return []interval{{incl(lo), unbounded}}
+	default: // "<="
+		switch {
+		case wildcard:
+			return []interval{{unbounded, excl(end)}}
+		case c.dirty:
+			return []interval{{unbounded, excl(floorKey(0, 1, 0, 1))}}
+		}
+		return []interval{{unbounded, incl(lo)}}
+	}
+}
+
+func (c *comparator) caretIntervals() []interval {
+	lo := c.lowest()
+	switch {
+	case c.major > 0 || c.minorDirty:
+		return []interval{{incl(lo), excl(c.nextMajor())}}
+	case c.minor > 0 || c.patchDirty:
+		return []interval{{incl(lo), excl(c.nextMinor())}}
+	}
+	return []interval{{incl(lo), excl(floorKey(0, 0, c.patch+1, 2))}}
+}
+
+func (c *comparator) tildeIntervals() []interval {
+	lo := c.lowest()
+	switch {
+	case c.isZero() && !c.minorDirty && !c.patchDirty:
+		return []interval{{incl(lo), unbounded}}
+	case c.minorDirty:
+		return []interval{{incl(lo), excl(c.nextMajor())}}
+	}
+	return []interval{{incl(lo), excl(c.nextMinor())}}
+}
+
+// excludesPrereleases reports whether the comparator rejects all prerelease
+// versions. Only comparators that name a prerelease accept them.
+func (c *comparator) excludesPrereleases() bool {
+	if c.op == "!=" && !c.dirty {
+		return false
+	}
+	return c.prerelease == ""
+}
+
+// check tests if k satisfies the comparator. excludePrereleases is the result
+// of Constraints.excludesPrereleases for c.
+func (c *comparator) check(o Ordering, k versionKey, excludePrereleases bool) bool {
+	if k.prerelease != "" && excludePrereleases {
+		return false
+	}
+	for _, i := range c.intervals() {
+		if i.contains(o, k) {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizeOp(op string) string {
+	switch op {
+	case "":
+		return "="
+	case "=>":
+		return ">="
+	case "=<":
+		return "<="
+	case "~>":
+		return "~"
+	default:
+		return op
+	}
+}
+
+func isWildcard(s string) bool {
+	switch s {
+	case "x", "X", "*":
+		return true
+	default:
+		return false
+	}
+}
+
+// newComparator builds a comparator from op and the submatches of
+// semverConstraintVersion. Prereleases that no go version has like "0",
+// "rc.1" or "-rc" are rejected because they can't be written in go syntax.
+func newComparator(op string, vm []string, noPatch bool) (*comparator, error) {
+	if vm[5] != "" && !isGoPrerelease(vm[5]) {
+		return nil, fmt.Errorf("%w: %q is not a go prerelease", ErrInvalidConstraint, vm[5])
+	}
+	c := &comparator{
+		op:         normalizeOp(op),
+		orig:       vm[0],
+		prerelease: vm[5],
+	}
+	parts := []string{vm[1], strings.TrimPrefix(vm[2], "."), strings.TrimPrefix(vm[3], ".")}
+	switch {
+	case isWildcard(parts[0]) || parts[0] == "":
+		c.dirty = true
+		return c, nil
+	case isWildcard(parts[1]) || parts[1] == "":
+		c.dirty, c.minorDirty = true, true
+		parts = parts[:1]
+	case isWildcard(parts[2]) || parts[2] == "":
+		c.dirty, c.patchDirty = true, true
+		parts = parts[:2]
+	default:
+		c.noPatch = noPatch
+	}
+	nums := []*uint64{&c.major, &c.minor, &c.patch}
+	for i, part := range parts {
+		n, err := strconv.ParseUint(part, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		*nums[i] = n
+	}
+	return c, nil
+}
+
+// parseComparators parses a semver range into groups of comparators. The
+// outer slice is ORed together and each inner slice is ANDed. noPatchAt holds
+// the offsets in semverRange of versions that omitted the patch.
+func parseComparators(semverRange string, noPatchAt map[int]bool) ([][]*comparator, error) {
+	initComparatorRegexp()
+	var groups [][]*comparator
+	offset := 0
+	for _, segment := range strings.Split(semverRange, "||") {
+		group, err := parseComparatorGroup(segment, offset, noPatchAt)
+		if err != nil {
+			return nil, err
+		}
+		groups = append(groups, group)
+		offset += len(segment) + len("||")
+	}
+	return groups, nil
+}
+
+type positionedComparator struct {
+	pos int
+	c   *comparator
+}
+
+func parseComparatorGroup(segment string, offset int, noPatchAt map[int]bool) ([]*comparator, error) {
+	var found []positionedComparator
+	add := func(op string, m []string, idx []int) error {
+		c, err := newComparator(op, m, noPatchAt[offset+idx[2]])
+		if err != nil {
+			return err
+		}
+		found = append(found, positionedComparator{pos: idx[0], c: c})
+		return nil
+	}
+
+	// hyphen ranges like "1.2.0 - 1.4.0" are ">= 1.2.0, <= 1.4.0"
+	blanked := []byte(segment)
+	for _, idx := range hyphenRangeRegexp.FindAllStringSubmatchIndex(segment, -1) {
+		m := submatches(segment, idx)
+		n := strings.Count(semverConstraintVersion, "(") + 1
+		err := add(">=", m[1:1+n], idx[2:2+2*n])
+		if err != nil {
+			return nil, err
+		}
+		err = add("<=", m[1+n:1+2*n], idx[2+2*n:2+4*n])
+		if err != nil {
+			return nil, err
+		}
+		for i := idx[0]; i < idx[1]; i++ {
+			blanked[i] = ' '
+		}
+	}
+
+	rest := string(blanked)
+	for _, idx := range findComparatorRegexp.FindAllStringSubmatchIndex(rest, -1) {
+		m := submatches(rest, idx)
+		err := add(m[1], m[2:], idx[4:])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// sorting by position keeps hyphen ranges in their written order
+	sort.SliceStable(found, func(i, j int) bool {
+		return found[i].pos < found[j].pos
+	})
+	group := make([]*comparator, len(found))
+	for i := range found {
+		group[i] = found[i].c
+	}
+	return group, nil
+}
+
+func submatches(s string, idx []int) []string {
+	m := make([]string, len(idx)/2)
+	for i := range m {
+		if idx[2*i] >= 0 {
+			m[i] = s[idx[2*i]:idx[2*i+1]]
+		}
+	}
+	return m
+}
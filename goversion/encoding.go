@@ -0,0 +1,129 @@
+package goversion
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// MarshalText implements encoding.TextMarshaler.
+func (v Version) MarshalText() ([]byte, error) {
+	return []byte(v.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. It returns an error
+// wrapping ErrInvalidGoVersion when text isn't a valid go version.
+func (v *Version) UnmarshalText(text []byte) error {
+	parsed, err := NewVersion(string(text))
+	if err != nil {
+		return err
+	}
+	*v = *parsed
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler. A Version is encoded as a string.
+func (v Version) MarshalJSON() ([]byte, error) {
+	return json.Marshal(v.String())
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It returns an error wrapping
+// ErrInvalidGoVersion when data isn't a string containing a valid go version.
+// Like encoding/json, it leaves v unchanged when data is null.
+func (v *Version) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var s string
+	err := json.Unmarshal(data, &s)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidGoVersion, data)
+	}
+	return v.UnmarshalText([]byte(s))
+}
+
+// Option prefixes in the text encoding of Constraints.
+const (
+	orderingTextPrefix    = "ordering="
+	prereleasesTextPrefix = "prereleases="
+)
+
+// MarshalText implements encoding.TextMarshaler. Constraints are encoded as
+// String preceded by any non-default ConstraintsOptions like
+// "ordering=gover prereleases=include >=1.21". UnmarshalText turns the result
+// back into Constraints matching the same versions.
+func (c Constraints) MarshalText() ([]byte, error) {
+	var parts []string
+	if c.ordering != SemverOrdering {
+		parts = append(parts, orderingTextPrefix+c.ordering.String())
+	}
+	if c.prereleases != PrereleaseDefault {
+		parts = append(parts, prereleasesTextPrefix+c.prereleases.String())
+	}
+	parts = append(parts, c.String())
+	return []byte(strings.Join(parts, " ")), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. It accepts the encoding
+// MarshalText writes, so options may precede the constraint. It returns an
+// error wrapping ErrInvalidConstraint when text isn't a valid constraint.
+func (c *Constraints) UnmarshalText(text []byte) error {
+	options := new(ConstraintsOptions)
+	s := strings.TrimSpace(string(text))
+	for {
+		field, rest, _ := strings.Cut(s, " ")
+		var err error
+		switch {
+		case strings.HasPrefix(field, orderingTextPrefix):
+			options.Ordering, err = parseOrdering(strings.TrimPrefix(field, orderingTextPrefix))
+		case strings.HasPrefix(field, prereleasesTextPrefix):
+			options.Prereleases, err = ParsePrereleasePolicy(strings.TrimPrefix(field, prereleasesTextPrefix))
+		default:
+			parsed, err := NewConstraintsWithOptions(s, options)
+			if err != nil {
+				return err
+			}
+			*c = *parsed
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%w %q: %v", ErrInvalidConstraint, text, err)
+		}
+		s = strings.TrimLeft(rest, " ")
+	}
+}
+
+// MarshalJSON implements json.Marshaler. Constraints are encoded as a string
+// holding MarshalText.
+func (c Constraints) MarshalJSON() ([]byte, error) {
+	text, err := c.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(string(text))
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It returns an error wrapping
+// ErrInvalidConstraint when data isn't a string containing a valid constraint.
+// Like encoding/json, it leaves c unchanged when data is null.
+func (c *Constraints) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var s string
+	err := json.Unmarshal(data, &s)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidConstraint, data)
+	}
+	return c.UnmarshalText([]byte(s))
+}
+
+// parseOrdering returns the Ordering named s.
+func parseOrdering(s string) (Ordering, error) {
+	for _, o := range []Ordering{SemverOrdering, GoverOrdering} {
+		if s == o.String() {
+			return o, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown ordering %q", s)
+}
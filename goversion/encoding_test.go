@@ -0,0 +1,150 @@
+package goversion
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+type encodingConfig struct {
+	Version    *Version     `json:"version" yaml:"version"`
+	Constraint *Constraints `json:"constraint" yaml:"constraint"`
+}
+
+func TestVersion_json(t *testing.T) {
+	for _, s := range []string{"go1.21", "go1.21.0", "go1.16rc1", "go1"} {
+		t.Run(s, func(t *testing.T) {
+			v := mustVersion(t, s)
+			data, err := json.Marshal(v)
+			require.NoError(t, err)
+			require.JSONEq(t, `"`+s+`"`, string(data))
+			var got Version
+			require.NoError(t, json.Unmarshal(data, &got))
+			require.Equal(t, s, got.String())
+		})
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		var got Version
+		err := json.Unmarshal([]byte(`"v1.16"`), &got)
+		require.True(t, errors.Is(err, ErrInvalidGoVersion))
+		err = json.Unmarshal([]byte(`116`), &got)
+		require.True(t, errors.Is(err, ErrInvalidGoVersion))
+	})
+}
+
+func TestConstraints_json(t *testing.T) {
+	var cfg encodingConfig
+	err := json.Unmarshal([]byte(`{"version": "go1.21.3", "constraint": ">=1.20 <1.22"}`), &cfg)
+	require.NoError(t, err)
+	assert.Equal(t, "go1.21.3", cfg.Version.String())
+	assert.True(t, cfg.Constraint.Check(cfg.Version))
+	data, err := json.Marshal(&cfg)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"version": "go1.21.3", "constraint": ">=go1.20.0 <go1.22.0"}`, string(data))
+
+	err = json.Unmarshal([]byte(`{"constraint": "asdf"}`), &cfg)
+	require.True(t, errors.Is(err, ErrInvalidConstraint))
+	err = json.Unmarshal([]byte(`{"constraint": 12}`), &cfg)
+	require.True(t, errors.Is(err, ErrInvalidConstraint))
+}
+
+func TestEncoding_jsonNull(t *testing.T) {
+	var cfg struct {
+		Version    Version     `json:"version"`
+		Constraint Constraints `json:"constraint"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(`{"version": "go1.21.3", "constraint": ">=1.21"}`), &cfg))
+	require.NoError(t, json.Unmarshal([]byte(`{"version": null, "constraint": null}`), &cfg))
+	assert.Equal(t, "go1.21.3", cfg.Version.String())
+	assert.Equal(t, ">=go1.21.0", cfg.Constraint.String())
+
+	v := mustVersion(t, "go1.20")
+	require.NoError(t, v.UnmarshalJSON([]byte("null")))
+	assert.Equal(t, "go1.20", v.String())
+}
+
+func TestConstraints_options(t *testing.T) {
+	c, err := NewConstraintsWithOptions(">=1.21", &ConstraintsOptions{
+		Ordering:    GoverOrdering,
+		Prereleases: PrereleaseInclude,
+	})
+	require.NoError(t, err)
+	text, err := c.MarshalText()
+	require.NoError(t, err)
+	require.Equal(t, "ordering=gover prereleases=include >=go1.21", string(text))
+	data, err := json.Marshal(c)
+	require.NoError(t, err)
+	require.JSONEq(t, `"ordering=gover prereleases=include >=go1.21"`, string(data))
+	for _, unmarshal := range []func(*Constraints) error{
+		func(got *Constraints) error { return got.UnmarshalText(text) },
+		func(got *Constraints) error { return json.Unmarshal(data, got) },
+		func(got *Constraints) error { return yaml.Unmarshal(text, got) },
+	} {
+		var got Constraints
+		require.NoError(t, unmarshal(&got))
+		require.Equal(t, GoverOrdering, got.Ordering())
+		require.Equal(t, PrereleaseInclude, got.Prereleases())
+		require.Equal(t, c.String(), got.String())
+		require.True(t, got.Check(mustVersion(t, "go1.21rc1")))
+	}
+
+	var got Constraints
+	require.NoError(t, got.UnmarshalText([]byte("prereleases=only 1.21.x")))
+	require.Equal(t, SemverOrdering, got.Ordering())
+	require.Equal(t, PrereleaseOnly, got.Prereleases())
+	for _, s := range []string{"ordering=lexical >=1.21", "prereleases=some >=1.21", "ordering=gover"} {
+		err = got.UnmarshalText([]byte(s))
+		require.Truef(t, errors.Is(err, ErrInvalidConstraint), "%q", s)
+	}
+}
+
+func TestConstraints_json_roundTrip(t *testing.T) {
+	constraints := append([]string{">=1.21.0-rc1", "1.21.0-beta1 || >1.21.3-rc2"}, compatibilityConstraints...)
+	versions := append([]string{"go1.21rc1", "go1.21.0", "go1.21.4rc1"}, compatibilityVersions...)
+	for _, options := range []ConstraintsOptions{
+		{},
+		{Ordering: GoverOrdering},
+		{Prereleases: PrereleaseInclude},
+		{Prereleases: PrereleaseFallback},
+	} {
+		for _, cs := range constraints {
+			c, err := NewConstraintsWithOptions(cs, &options)
+			require.NoError(t, err)
+			data, err := json.Marshal(c)
+			require.NoError(t, err)
+			var got Constraints
+			require.NoError(t, json.Unmarshal(data, &got))
+			for _, vs := range versions {
+				v := mustVersion(t, vs)
+				require.Equalf(t, c.Check(v), got.Check(v), "constraint %q encoded as %s version %q", cs, data, vs)
+			}
+		}
+	}
+
+	// numeric prereleases would change meaning when rendered, so they never
+	// parse
+	for _, cs := range []string{">=1.21.0-0", "1.21.0-1", "<1.20.3-4"} {
+		var got Constraints
+		err := json.Unmarshal([]byte(`"`+cs+`"`), &got)
+		require.Truef(t, errors.Is(err, ErrInvalidConstraint), "%q", cs)
+	}
+}
+
+func TestEncoding_yaml(t *testing.T) {
+	var cfg encodingConfig
+	err := yaml.Unmarshal([]byte("version: go1.22rc1\nconstraint: 1.22.x || >=go1.22rc1\n"), &cfg)
+	require.NoError(t, err)
+	assert.Equal(t, "go1.22rc1", cfg.Version.String())
+	assert.True(t, cfg.Constraint.Check(cfg.Version))
+	data, err := yaml.Marshal(&cfg)
+	require.NoError(t, err)
+	require.Equal(t, "version: go1.22rc1\nconstraint: 1.22.x || >=go1.22rc1\n", string(data))
+
+	err = yaml.Unmarshal([]byte("version: go1.x\n"), &cfg)
+	require.True(t, errors.Is(err, ErrInvalidGoVersion))
+}
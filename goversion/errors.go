@@ -0,0 +1,240 @@
+package goversion
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// ParseError describes why a version or constraint could not be parsed. It
+// matches ErrInvalidGoVersion or ErrInvalidConstraint with errors.Is.
+type ParseError struct {
+	// Input is the string that failed to parse.
+	Input string
+
+	// Pos is the byte offset in Input of the offending token or -1 when no
+	// single token is at fault.
+	Pos int
+
+	// Token is the part of Input that couldn't be parsed.
+	Token string
+
+	// Suggestion is a hint for fixing the input. It may be empty.
+	Suggestion string
+
+	err error
+}
+
+// Error implements error.
+func (e *ParseError) Error() string {
+	msg := fmt.Sprintf("%v %q", e.err, e.Input)
+	if e.Pos >= 0 {
+		msg += fmt.Sprintf(": unexpected %q at position %d", e.Token, e.Pos)
+	}
+	if e.Suggestion != "" {
+		msg += ": " + e.Suggestion
+	}
+	return msg
+}
+
+// Unwrap returns ErrInvalidGoVersion or ErrInvalidConstraint.
+func (e *ParseError) Unwrap() error {
+	return e.err
+}
+
+// newVersionError returns a *ParseError for an invalid version.
+func newVersionError(input string) *ParseError {
+	e := &ParseError{
+		Input: input,
+		Pos:   -1,
+		err:   ErrInvalidGoVersion,
+	}
+	if pos := versionErrorPos(input); pos >= 0 {
+		e.Pos = pos
+		e.Token = input[pos:]
+	}
+	e.Suggestion = suggestVersion(input)
+	return e
+}
+
+// versionErrorPos returns the offset of the first byte of version that
+// doesn't fit the go version grammar or -1 if it fits.
+func versionErrorPos(version string) int {
+	i := 0
+	if strings.HasPrefix(version, "go") {
+		i = 2
+	}
+	// number consumes a version number and returns the offset of an invalid
+	// number like "02", missingPos when there are no digits or -1.
+	number := func(missingPos int) int {
+		start := i
+		for i < len(version) && version[i] >= '0' && version[i] <= '9' {
+			i++
+		}
+		if i == start {
+			return missingPos
+		}
+		if _, _, ok := parseVersionNumber(version[start:i]); !ok {
+			return start
+		}
+		return -1
+	}
+	if pos := number(i); pos >= 0 {
+		return pos
+	}
+	for n := 0; n < 2 && i < len(version) && version[i] == '.'; n++ {
+		dot := i
+		i++
+		if pos := number(dot); pos >= 0 {
+			return pos
+		}
+	}
+	for i < len(version) && isAlnum(version[i]) {
+		i++
+	}
+	if i < len(version) {
+		return i
+	}
+	return -1
+}
+
+func isAlnum(b byte) bool {
+	return b >= '0' && b <= '9' || b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z'
+}
+
+// wildcardComponentRegexp matches a wildcard version component like the x in
+// "1.x".
+var wildcardComponentRegexp *regexp.Regexp
+
+var initWildcardRegexpOnce sync.Once
+
+func initWildcardRegexp() {
+	initWildcardRegexpOnce.Do(func() {
+		wildcardComponentRegexp = regexp.MustCompile(`(^|\.)[xX*](\.|$)`)
+	})
+}
+
+// looksLikeConstraint returns true if s has operators or wildcards.
+func looksLikeConstraint(s string) bool {
+	if strings.ContainsAny(s, "<>=~^|,") {
+		return true
+	}
+	initWildcardRegexp()
+	return wildcardComponentRegexp.MatchString(s)
+}
+
+// suggestVersion returns a hint for fixing an invalid version.
+func suggestVersion(input string) string {
+	s := strings.TrimSpace(input)
+	if s == "" {
+		return "version is empty"
+	}
+	if looksLikeConstraint(s) {
+		return fmt.Sprintf("%s is a constraint, not a version", s)
+	}
+	if b, ok := parseBuildVersion(s); ok && b.Version != nil && strings.ContainsAny(s, " \t\n") {
+		return fmt.Sprintf("this looks like go version output for %s; use ParseBuildVersion", b.Version)
+	}
+	candidate := strings.ToLower(strings.Join(strings.Fields(s), ""))
+	vPrefix := strings.HasPrefix(candidate, "v")
+	candidate = strings.TrimPrefix(candidate, "v")
+	// semver style prerelease like 1.21.0-rc.1
+	candidate = goPrerelease(candidate)
+	if candidate == input || versionErrorPos(candidate) != -1 {
+		return ""
+	}
+	v, ok := parseVersion(candidate)
+	if !ok {
+		return ""
+	}
+	if vPrefix {
+		return fmt.Sprintf("%s is not a Go version; did you mean %s?", s, v)
+	}
+	return fmt.Sprintf("did you mean %s?", v)
+}
+
+// newConstraintError returns a *ParseError for an invalid constraint.
+func newConstraintError(input string) *ParseError {
+	e := &ParseError{
+		Input: input,
+		Pos:   -1,
+		err:   ErrInvalidConstraint,
+	}
+	if strings.TrimSpace(input) == "" {
+		e.Suggestion = "constraint is empty"
+		return e
+	}
+	if IsAlias(input) {
+		e.Suggestion = fmt.Sprintf("%s is an alias that must be resolved against candidate versions", strings.TrimSpace(input))
+		return e
+	}
+	pos, token := constraintErrorToken(input)
+	if pos >= 0 {
+		e.Pos, e.Token = pos, token
+		e.Suggestion = suggestConstraintToken(token)
+	}
+	return e
+}
+
+// constraintErrorToken finds the first token in input that isn't a valid
+// constraint on its own.
+func constraintErrorToken(input string) (int, string) {
+	type token struct {
+		pos int
+		s   string
+	}
+	var tokens []token
+	start := -1
+	for i, r := range input + " " {
+		isSep := unicode.IsSpace(r) || r == ','
+		switch {
+		case isSep && start >= 0:
+			tokens = append(tokens, token{pos: start, s: input[start:i]})
+			start = -1
+		case !isSep && start < 0:
+			start = i
+		}
+	}
+	for i := 0; i < len(tokens); i++ {
+		tok := tokens[i]
+		if tok.s == "||" || tok.s == "-" {
+			continue
+		}
+		s := tok.s
+		// operators may be separated from their version by whitespace
+		if strings.Trim(s, "<>=!~^") == "" && i+1 < len(tokens) {
+			i++
+			s = input[tok.pos : tokens[i].pos+len(tokens[i].s)]
+		}
+		for _, part := range strings.Split(s, "||") {
+			if part == "" {
+				continue
+			}
+			if _, err := parseConstraints(part, nil); err != nil {
+				return tok.pos + strings.Index(s, part), part
+			}
+		}
+	}
+	return -1, ""
+}
+
+// suggestConstraintToken returns a hint for fixing an invalid comparator.
+func suggestConstraintToken(token string) string {
+	version := strings.TrimLeft(token, "<>=!~^ ")
+	op := token[:len(token)-len(version)]
+	if alias := strings.ToLower(version); IsAlias(alias) {
+		if op == "" && alias != version {
+			return fmt.Sprintf("did you mean %s?", alias)
+		}
+		return fmt.Sprintf("%s is an alias and can't be combined with other constraints", alias)
+	}
+	if strings.HasPrefix(strings.ToLower(version), "go") && !strings.HasPrefix(version, "go") {
+		suggestion := op + "go" + version[2:]
+		if _, err := parseConstraints(suggestion, nil); err == nil {
+			return fmt.Sprintf("did you mean %s?", suggestion)
+		}
+	}
+	return ""
+}
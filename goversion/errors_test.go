@@ -0,0 +1,83 @@
+package goversion
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewVersion_parseError(t *testing.T) {
+	for _, td := range []struct {
+		input      string
+		pos        int
+		token      string
+		suggestion string
+	}{
+		{input: "v1.16", pos: 0, token: "v1.16", suggestion: "v1.16 is not a Go version; did you mean go1.16?"},
+		{input: "v1.16rc1", pos: 0, token: "v1.16rc1", suggestion: "v1.16rc1 is not a Go version; did you mean go1.16rc1?"},
+		{input: "1.15.x", pos: 4, token: ".x", suggestion: "1.15.x is a constraint, not a version"},
+		{input: "go1.15.x", pos: 6, token: ".x", suggestion: "go1.15.x is a constraint, not a version"},
+		{input: ">=1.15", pos: 0, token: ">=1.15", suggestion: ">=1.15 is a constraint, not a version"},
+		{input: "Go1.21", pos: 0, token: "Go1.21", suggestion: "did you mean go1.21?"},
+		{input: "go 1.21", pos: 2, token: " 1.21", suggestion: "did you mean go1.21?"},
+		{input: " go1.21 ", pos: 0, token: " go1.21 ", suggestion: "did you mean go1.21?"},
+		{input: "1.21.0-rc.1", pos: 6, token: "-rc.1", suggestion: "did you mean go1.21rc1?"},
+		{input: "go1.21.3-2", pos: 8, token: "-2"},
+		{input: "1.21.0-1", pos: 6, token: "-1"},
+		{input: "go1.2.3.4", pos: 7, token: ".4"},
+		{input: "go01.2", pos: 2, token: "01.2"},
+		{input: "go1.21.00", pos: 7, token: "00"},
+		{input: "", pos: 0, token: "", suggestion: "version is empty"},
+	} {
+		t.Run(td.input, func(t *testing.T) {
+			_, err := NewVersion(td.input)
+			require.True(t, errors.Is(err, ErrInvalidGoVersion))
+			var pe *ParseError
+			require.True(t, errors.As(err, &pe))
+			assert.Equal(t, td.input, pe.Input)
+			assert.Equal(t, td.pos, pe.Pos)
+			assert.Equal(t, td.token, pe.Token)
+			assert.Equal(t, td.suggestion, pe.Suggestion)
+		})
+	}
+}
+
+func TestParseError_Error(t *testing.T) {
+	_, err := NewVersion("v1.16")
+	require.EqualError(t, err, `invalid go version "v1.16": unexpected "v1.16" at position 0: v1.16 is not a Go version; did you mean go1.16?`)
+	_, err = NewConstraints(">=1.15 <Go1.17")
+	require.EqualError(t, err, `invalid go constraint ">=1.15 <Go1.17": unexpected "<Go1.17" at position 7: did you mean <go1.17?`)
+}
+
+func TestNewConstraints_parseError(t *testing.T) {
+	for _, td := range []struct {
+		input      string
+		pos        int
+		token      string
+		suggestion string
+	}{
+		{input: "asdf", pos: 0, token: "asdf"},
+		{input: "Go1.16", pos: 0, token: "Go1.16", suggestion: "did you mean go1.16?"},
+		{input: ">= GO1.16", pos: 0, token: ">= GO1.16", suggestion: "did you mean >= go1.16?"},
+		{input: "1.15.x || >=Go1.17", pos: 10, token: ">=Go1.17", suggestion: "did you mean >=go1.17?"},
+		{input: "1.15.x||Go1.17", pos: 8, token: "Go1.17", suggestion: "did you mean go1.17?"},
+		{input: ">=1.15 <gox", pos: 7, token: "<gox"},
+		{input: "Stable", pos: 0, token: "Stable", suggestion: "did you mean stable?"},
+		{input: "stable", pos: -1, suggestion: "stable is an alias that must be resolved against candidate versions"},
+		{input: ">=1.2 latest", pos: 6, token: "latest", suggestion: "latest is an alias and can't be combined with other constraints"},
+		{input: " ", pos: -1, suggestion: "constraint is empty"},
+	} {
+		t.Run(td.input, func(t *testing.T) {
+			_, err := NewConstraints(td.input)
+			require.True(t, errors.Is(err, ErrInvalidConstraint))
+			var pe *ParseError
+			require.True(t, errors.As(err, &pe))
+			assert.Equal(t, td.input, pe.Input)
+			assert.Equal(t, td.pos, pe.Pos)
+			assert.Equal(t, td.token, pe.Token)
+			assert.Equal(t, td.suggestion, pe.Suggestion)
+		})
+	}
+}
@@ -0,0 +1,170 @@
+package goversion
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Explanation describes how a Version was checked against Constraints.
+type Explanation struct {
+	Version *Version
+	Matched bool
+
+	// Branches holds the result for each group of comparators separated by
+	// "||". The version matches if any branch matches.
+	Branches []BranchExplanation
+}
+
+// BranchExplanation is the result of checking a version against one group of
+// comparators. The branch matches if every comparator matches.
+type BranchExplanation struct {
+	Matched     bool
+	Comparators []ComparatorExplanation
+}
+
+// ComparatorExplanation is the result of checking a version against a single
+// comparator like ">=go1.15.0" or "1.16.x".
+type ComparatorExplanation struct {
+	// Comparator is the comparator in the canonical syntax of
+	// Constraints.String.
+	Comparator string
+	Matched    bool
+
+	// PrereleaseExcluded is set when the version was rejected only because it
+	// is a prerelease and the comparator doesn't name a prerelease.
+	PrereleaseExcluded bool
+
+	// Reason is a human readable description of the result.
+	Reason string
+}
+
+// Explain checks v against c and reports which branches and comparators
+// accepted or rejected it. Every comparator is evaluated even after the result
+// is known. It is ExplainIn with v as the only candidate, so under
+// PrereleaseFallback a prerelease is included unless v is a matching release.
+func (c Constraints) Explain(v *Version) *Explanation {
+	return c.ExplainIn(v, []*Version{v})
+}
+
+// ExplainIn is like Explain but applies PrereleaseFallback across versions the
+// way FilterVersions does, so v matches exactly when FilterVersions(versions)
+// would select it. versions should include v.
+func (c Constraints) ExplainIn(v *Version, versions []*Version) *Explanation {
+	if c.prereleases == PrereleaseFallback {
+		c.prereleases = c.fallbackPolicy(versions)
+	}
+	k := v.key()
+	e := &Explanation{
+		Version:  v,
+		Branches: make([]BranchExplanation, len(c.groups)),
+	}
+	for i, group := range c.groups {
+		branch := BranchExplanation{
+			Matched:     true,
+			Comparators: make([]ComparatorExplanation, len(group)),
+		}
+		for j, cmp := range group {
+			ce := c.explainComparator(cmp, v, k)
+			branch.Comparators[j] = ce
+			if !ce.Matched {
+				branch.Matched = false
+			}
+		}
+		e.Branches[i] = branch
+		if branch.Matched {
+			e.Matched = true
+		}
+	}
+	return e
+}
+
+// Validate checks v against c. When v doesn't match, the errors describe why
+// each comparator rejected it.
+func (c Constraints) Validate(v *Version) (bool, []error) {
+	e := c.Explain(v)
+	if e.Matched {
+		return true, nil
+	}
+	var errs []error
+	for _, branch := range e.Branches {
+		for _, ce := range branch.Comparators {
+			if !ce.Matched {
+				errs = append(errs, errors.New(ce.Reason))
+			}
+		}
+	}
+	return false, errs
+}
+
+// String returns a multi-line description of the explanation.
+func (e *Explanation) String() string {
+	var sb strings.Builder
+	result := "does not match"
+	if e.Matched {
+		result = "matches"
+	}
+	fmt.Fprintf(&sb, "%s %s\n", e.Version, result)
+	for i, branch := range e.Branches {
+		result = "rejected"
+		if branch.Matched {
+			result = "accepted"
+		}
+		if len(e.Branches) > 1 {
+			fmt.Fprintf(&sb, "  branch %d %s\n", i+1, result)
+		}
+		for _, ce := range branch.Comparators {
+			fmt.Fprintf(&sb, "    %s\n", ce.Reason)
+		}
+	}
+	return sb.String()
+}
+
+func (c Constraints) explainComparator(cmp *comparator, v *Version, k versionKey) ComparatorExplanation {
+	ce := ComparatorExplanation{
+		Comparator: cmp.canonicalString(c.ordering),
+	}
+	excluded := c.excludesPrereleases(cmp)
+	switch {
+	case k.prerelease != "" && excluded && c.prereleases == PrereleaseExclude:
+		ce.PrereleaseExcluded = true
+		ce.Reason = fmt.Sprintf("%s is a prerelease and prereleases are excluded", v)
+	case k.prerelease != "" && excluded:
+		ce.PrereleaseExcluded = true
+		ce.Reason = fmt.Sprintf("%s is a prerelease and %q only matches releases", v, ce.Comparator)
+	case k.prerelease == "" && c.prereleases == PrereleaseOnly:
+		ce.Reason = fmt.Sprintf("%s is not a prerelease and only prereleases are matched", v)
+	case cmp.check(c.ordering, k, excluded):
+		ce.Matched = true
+		ce.Reason = fmt.Sprintf("%s satisfies %q", v, ce.Comparator)
+	case cmp.op == "~" || cmp.op == "^":
+		ce.Reason = fmt.Sprintf("%s is not in the range %q", v, ce.Comparator)
+	default:
+		ce.Reason = fmt.Sprintf("%s %s %s", v, cmp.rejection(), cmp.canonicalVersion(c.ordering))
+	}
+	return ce
+}
+
+// rejection describes how a version that fails the comparator relates to it.
+func (c *comparator) rejection() string {
+	switch c.op {
+	case "=":
+		if c.dirty {
+			return "is not in"
+		}
+		return "is not equal to"
+	case "!=":
+		if c.dirty {
+			return "is in"
+		}
+		return "is equal to"
+	case ">":
+		return "is less than or equal to"
+	case "<":
+		return "is greater than or equal to"
+	case ">=":
+		return "is less than"
+	default:
+		return "is greater than"
+	}
+}
@@ -0,0 +1,103 @@
+package goversion
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConstraints_Explain(t *testing.T) {
+	c, err := NewConstraints(">=1.20 <1.21 || 1.21.x")
+	require.NoError(t, err)
+
+	t.Run("match", func(t *testing.T) {
+		got := c.Explain(mustVersion(t, "go1.20.14"))
+		require.True(t, got.Matched)
+		require.Len(t, got.Branches, 2)
+		assert.True(t, got.Branches[0].Matched)
+		assert.False(t, got.Branches[1].Matched)
+		assert.Equal(t, []ComparatorExplanation{
+			{Comparator: ">=go1.20.0", Matched: true, Reason: `go1.20.14 satisfies ">=go1.20.0"`},
+			{Comparator: "<go1.21.0", Matched: true, Reason: `go1.20.14 satisfies "<go1.21.0"`},
+		}, got.Branches[0].Comparators)
+		assert.Equal(t, []ComparatorExplanation{
+			{Comparator: "1.21.x", Reason: "go1.20.14 is not in 1.21.x"},
+		}, got.Branches[1].Comparators)
+	})
+
+	t.Run("prerelease", func(t *testing.T) {
+		got := c.Explain(mustVersion(t, "go1.21rc2"))
+		require.False(t, got.Matched)
+		assert.Equal(t, ComparatorExplanation{
+			Comparator:         "1.21.x",
+			PrereleaseExcluded: true,
+			Reason:             `go1.21rc2 is a prerelease and "1.21.x" only matches releases`,
+		}, got.Branches[1].Comparators[0])
+		require.Equal(t, `go1.21rc2 does not match
+  branch 1 rejected
+    go1.21rc2 is a prerelease and ">=go1.20.0" only matches releases
+    go1.21rc2 is a prerelease and "<go1.21.0" only matches releases
+  branch 2 rejected
+    go1.21rc2 is a prerelease and "1.21.x" only matches releases
+`, got.String())
+	})
+
+	t.Run("gover", func(t *testing.T) {
+		gc, err := NewConstraintsWithOptions("<=1.21", &ConstraintsOptions{Ordering: GoverOrdering})
+		require.NoError(t, err)
+		got := gc.Explain(mustVersion(t, "go1.21.0"))
+		require.False(t, got.Matched)
+		assert.Equal(t, "go1.21.0 is greater than go1.21", got.Branches[0].Comparators[0].Reason)
+	})
+
+	t.Run("fallback", func(t *testing.T) {
+		fc, err := NewConstraintsWithOptions("1.21.x", &ConstraintsOptions{Prereleases: PrereleaseFallback})
+		require.NoError(t, err)
+		v := mustVersion(t, "go1.21rc2")
+		require.Equal(t, []*Version{v}, fc.FilterVersions([]*Version{v}))
+		got := fc.Explain(v)
+		require.True(t, got.Matched)
+		assert.Equal(t, ComparatorExplanation{
+			Comparator: "1.21.x",
+			Matched:    true,
+			Reason:     `go1.21rc2 satisfies "1.21.x"`,
+		}, got.Branches[0].Comparators[0])
+		require.False(t, fc.Explain(mustVersion(t, "go1.22rc1")).Matched)
+	})
+
+	t.Run("fallback candidates", func(t *testing.T) {
+		fc, err := NewConstraintsWithOptions("1.21.x", &ConstraintsOptions{Prereleases: PrereleaseFallback})
+		require.NoError(t, err)
+		for _, candidates := range [][]string{
+			{"go1.21.5", "go1.21rc2"},
+			{"go1.21rc1", "go1.21rc2", "go1.22.0"},
+			{"go1.20.1", "go1.21rc2", "go1.21.0"},
+		} {
+			versions := make([]*Version, len(candidates))
+			for i, s := range candidates {
+				versions[i] = mustVersion(t, s)
+			}
+			filtered := fc.FilterVersions(versions)
+			for _, v := range versions {
+				assert.Equal(t, Collection(filtered).Contains(v), fc.ExplainIn(v, versions).Matched, "%s in %v", v, candidates)
+			}
+		}
+		got := fc.ExplainIn(mustVersion(t, "go1.21rc2"), []*Version{mustVersion(t, "go1.21.5"), mustVersion(t, "go1.21rc2")})
+		require.False(t, got.Matched)
+		assert.True(t, got.Branches[0].Comparators[0].PrereleaseExcluded)
+	})
+}
+
+func TestConstraints_Validate(t *testing.T) {
+	c, err := NewConstraints("~1.20.3 || ^2")
+	require.NoError(t, err)
+	ok, errs := c.Validate(mustVersion(t, "go1.20.4"))
+	require.True(t, ok)
+	require.Empty(t, errs)
+	ok, errs = c.Validate(mustVersion(t, "go1.21"))
+	require.False(t, ok)
+	require.Len(t, errs, 2)
+	assert.EqualError(t, errs[0], `go1.21 is not in the range "~go1.20.3"`)
+	assert.EqualError(t, errs[1], `go1.21 is not in the range "^go2.0.0"`)
+}
@@ -0,0 +1,179 @@
+package goversion
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidGotoolchain is returned when a GOTOOLCHAIN setting is not valid
+var ErrInvalidGotoolchain = fmt.Errorf("invalid GOTOOLCHAIN")
+
+// ToolchainMode is how the go command may switch away from the minimum
+// toolchain in a GOTOOLCHAIN setting.
+type ToolchainMode int
+
+const (
+	// ToolchainFixed never switches. It is the mode of "local" and of a bare
+	// toolchain name like "go1.21.3".
+	ToolchainFixed ToolchainMode = iota
+
+	// ToolchainAuto switches to a newer toolchain when go.mod or go.work
+	// requires one, downloading it if necessary. It is the mode of "auto" and
+	// of a "+auto" suffix.
+	ToolchainAuto
+
+	// ToolchainPath is like ToolchainAuto but only looks for toolchains in
+	// PATH. It is the mode of "path" and of a "+path" suffix.
+	ToolchainPath
+)
+
+// String returns the GOTOOLCHAIN suffix for the mode without the "+".
+func (m ToolchainMode) String() string {
+	switch m {
+	case ToolchainFixed:
+		return ""
+	case ToolchainAuto:
+		return "auto"
+	case ToolchainPath:
+		return "path"
+	default:
+		return "ToolchainMode(" + strconv.Itoa(int(m)) + ")"
+	}
+}
+
+// Gotoolchain is a parsed GOTOOLCHAIN setting like "go1.21.3+auto".
+type Gotoolchain struct {
+	// Min is the name of the minimum toolchain like "go1.21.3". It is empty
+	// when the minimum is the local toolchain.
+	Min string
+
+	// MinVersion is the version of Min. It is nil when Min is empty.
+	MinVersion *Version
+
+	Mode ToolchainMode
+}
+
+// ParseGotoolchain parses a GOTOOLCHAIN setting. The settings are "local",
+// "auto", "path" or a toolchain name like "go1.21.3", optionally followed by
+// "+auto" or "+path". An empty setting is "auto", which is the value in the
+// go.env file distributed with the go command.
+func ParseGotoolchain(s string) (*Gotoolchain, error) {
+	switch s {
+	case "", "auto":
+		return &Gotoolchain{Mode: ToolchainAuto}, nil
+	case "path":
+		return &Gotoolchain{Mode: ToolchainPath}, nil
+	}
+	name, suffix, plus := strings.Cut(s, "+")
+	g := new(Gotoolchain)
+	switch {
+	case !plus:
+	case suffix == "auto":
+		g.Mode = ToolchainAuto
+	case suffix == "path":
+		g.Mode = ToolchainPath
+	default:
+		return nil, fmt.Errorf("%w %q: only version suffixes are +auto and +path", ErrInvalidGotoolchain, s)
+	}
+	if name == "local" {
+		return g, nil
+	}
+	v, ok := toolchainVersion(name)
+	if !ok {
+		if plus {
+			return nil, fmt.Errorf("%w %q: invalid minimum toolchain %q", ErrInvalidGotoolchain, s, name)
+		}
+		return nil, fmt.Errorf("%w %q", ErrInvalidGotoolchain, s)
+	}
+	g.Min, g.MinVersion = name, v
+	return g, nil
+}
+
+// String returns the setting in the form ParseGotoolchain accepts.
+func (g *Gotoolchain) String() string {
+	name := g.Min
+	if name == "" {
+		if g.Mode != ToolchainFixed {
+			return g.Mode.String()
+		}
+		name = "local"
+	}
+	if g.Mode == ToolchainFixed {
+		return name
+	}
+	return name + "+" + g.Mode.String()
+}
+
+// toolchainVersion returns the version of a toolchain name like "go1.21.3",
+// "go1.21.3-bigcorp" or "gccgo-go1.21.3" the same way the go command does.
+func toolchainVersion(name string) (*Version, bool) {
+	if strings.ContainsAny(name, `\/`) {
+		return nil, false
+	}
+	var s string
+	switch {
+	case strings.HasPrefix(name, "go"):
+		s = name[2:]
+	case strings.Contains(name, "-go"):
+		s = name[strings.Index(name, "-go")+3:]
+	default:
+		return nil, false
+	}
+	if i := strings.IndexAny(s, " \t-"); i != -1 {
+		s = s[:i]
+	}
+	v, ok := parseVersion("go" + s)
+	if !ok {
+		return nil, false
+	}
+	return &v, true
+}
+
+// ToolchainSelection is the toolchain the go command would run.
+type ToolchainSelection struct {
+	// Name is the name of the toolchain like "go1.22.0" or "go1.22.0-custom".
+	Name string
+
+	// Version is the version of the toolchain.
+	Version *Version
+
+	// Local is set when the selected toolchain is the local toolchain, so the
+	// go command runs without switching.
+	Local bool
+
+	// PathOnly is set when the go command would only look for the toolchain
+	// in PATH instead of downloading it.
+	PathOnly bool
+}
+
+// Select returns the toolchain the go command would select when the local
+// toolchain is local and the go.mod or go.work file in use is mod. mod may be
+// nil when there is no such file. Like the go command, Select doesn't check
+// that the result satisfies mod's go directive.
+func (g *Gotoolchain) Select(local *Version, mod *ModFile) *ToolchainSelection {
+	name, version := local.String(), local
+	if g.MinVersion != nil {
+		name, version = g.Min, g.MinVersion
+	}
+	if g.Mode != ToolchainFixed && mod != nil && !mod.ToolchainDefault {
+		if mod.Toolchain != nil && GoverOrdering.Compare(mod.Toolchain, version) > 0 {
+			name, version = mod.ToolchainName, mod.Toolchain
+		}
+		if mod.Go != nil && GoverOrdering.Compare(mod.Go, version) > 0 {
+			version = mod.Go
+			// a language version like go1.22 was never released, so use
+			// its first release instead
+			if version.IsLang() && (version.major > 1 || version.minor >= 21) {
+				version = buildVersion(version.major, version.minor, 0, "", false, false)
+			}
+			name = version.String()
+		}
+	}
+	return &ToolchainSelection{
+		Name:     name,
+		Version:  version,
+		Local:    name == local.String(),
+		PathOnly: g.Mode == ToolchainPath,
+	}
+}
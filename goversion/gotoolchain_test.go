@@ -0,0 +1,214 @@
+package goversion
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseGotoolchain(t *testing.T) {
+	for _, td := range []struct {
+		input   string
+		want    string
+		min     string
+		mode    ToolchainMode
+		wantErr string
+	}{
+		{input: "", want: "auto", mode: ToolchainAuto},
+		{input: "auto", want: "auto", mode: ToolchainAuto},
+		{input: "path", want: "path", mode: ToolchainPath},
+		{input: "local", want: "local", mode: ToolchainFixed},
+		{input: "local+auto", want: "auto", mode: ToolchainAuto},
+		{input: "local+path", want: "path", mode: ToolchainPath},
+		{input: "go1.21.3", want: "go1.21.3", min: "go1.21.3", mode: ToolchainFixed},
+		{input: "go1.21.3+auto", want: "go1.21.3+auto", min: "go1.21.3", mode: ToolchainAuto},
+		{input: "go1.21.3+path", want: "go1.21.3+path", min: "go1.21.3", mode: ToolchainPath},
+		{input: "go1.21.3-bigcorp+auto", want: "go1.21.3-bigcorp+auto", min: "go1.21.3", mode: ToolchainAuto},
+		{input: "gccgo-go1.21.3", want: "gccgo-go1.21.3", min: "go1.21.3", mode: ToolchainFixed},
+		{input: "go1.21.3+local", wantErr: `invalid GOTOOLCHAIN "go1.21.3+local": only version suffixes are +auto and +path`},
+		{input: "1.21.3+auto", wantErr: `invalid GOTOOLCHAIN "1.21.3+auto": invalid minimum toolchain "1.21.3"`},
+		{input: "latest", wantErr: `invalid GOTOOLCHAIN "latest"`},
+		{input: "go1.21/../go1.22", wantErr: `invalid GOTOOLCHAIN "go1.21/../go1.22"`},
+	} {
+		t.Run(td.input, func(t *testing.T) {
+			got, err := ParseGotoolchain(td.input)
+			if td.wantErr != "" {
+				require.EqualError(t, err, td.wantErr)
+				require.True(t, errors.Is(err, ErrInvalidGotoolchain))
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, td.want, got.String())
+			require.Equal(t, td.mode, got.Mode)
+			if td.min == "" {
+				require.Empty(t, got.Min)
+				require.Nil(t, got.MinVersion)
+				return
+			}
+			require.Equal(t, td.min, got.MinVersion.String())
+		})
+	}
+}
+
+// TestGotoolchain_Select checks the toolchain selection rules documented at
+// https://go.dev/doc/toolchain#select.
+func TestGotoolchain_Select(t *testing.T) {
+	for _, td := range []struct {
+		name        string
+		gotoolchain string
+		local       string
+		mod         string
+		want        string
+		wantVersion string
+		wantLocal   bool
+		pathOnly    bool
+	}{
+		{
+			name:        "go line satisfied by local",
+			gotoolchain: "auto",
+			local:       "go1.21.0",
+			mod:         "go 1.21.0",
+			want:        "go1.21.0",
+			wantLocal:   true,
+		},
+		{
+			name:        "go line newer than local",
+			gotoolchain: "auto",
+			local:       "go1.21.0",
+			mod:         "go 1.22.0",
+			want:        "go1.22.0",
+		},
+		{
+			name:        "language version go line uses first release",
+			gotoolchain: "auto",
+			local:       "go1.21.0",
+			mod:         "go 1.22",
+			want:        "go1.22.0",
+		},
+		{
+			name:        "language version before 1.21",
+			gotoolchain: "auto",
+			local:       "go1.19.13",
+			mod:         "go 1.20",
+			want:        "go1.20",
+		},
+		{
+			name:        "prerelease go line",
+			gotoolchain: "auto",
+			local:       "go1.20.5",
+			mod:         "go 1.21rc1",
+			want:        "go1.21rc1",
+		},
+		{
+			name:        "toolchain line newer than local",
+			gotoolchain: "auto",
+			local:       "go1.21.0",
+			mod:         "go 1.21.0\ntoolchain go1.21.4",
+			want:        "go1.21.4",
+		},
+		{
+			name:        "custom toolchain line",
+			gotoolchain: "auto",
+			local:       "go1.21.0",
+			mod:         "go 1.21.0\ntoolchain go1.21.3-custom",
+			want:        "go1.21.3-custom",
+			wantVersion: "go1.21.3",
+		},
+		{
+			name:        "toolchain line older than local",
+			gotoolchain: "auto",
+			local:       "go1.22.0",
+			mod:         "go 1.21.0\ntoolchain go1.21.4",
+			want:        "go1.22.0",
+			wantLocal:   true,
+		},
+		{
+			name:        "go line newer than toolchain line",
+			gotoolchain: "auto",
+			local:       "go1.21.0",
+			mod:         "go 1.22.1\ntoolchain go1.21.4",
+			want:        "go1.22.1",
+		},
+		{
+			name:        "toolchain default ignores go line",
+			gotoolchain: "go1.21.3+auto",
+			local:       "go1.21.0",
+			mod:         "go 1.22.0\ntoolchain default",
+			want:        "go1.21.3",
+		},
+		{
+			name:        "local never switches",
+			gotoolchain: "local",
+			local:       "go1.21.0",
+			mod:         "go 1.22.0",
+			want:        "go1.21.0",
+			wantLocal:   true,
+		},
+		{
+			name:        "fixed toolchain ignores go.mod",
+			gotoolchain: "go1.21.3",
+			local:       "go1.22.0",
+			mod:         "go 1.22.0\ntoolchain go1.22.2",
+			want:        "go1.21.3",
+		},
+		{
+			name:        "minimum newer than go line",
+			gotoolchain: "go1.22.0+auto",
+			local:       "go1.21.0",
+			mod:         "go 1.21",
+			want:        "go1.22.0",
+		},
+		{
+			name:        "minimum older than go line",
+			gotoolchain: "go1.22.0+path",
+			local:       "go1.21.0",
+			mod:         "go 1.23.1",
+			want:        "go1.23.1",
+			pathOnly:    true,
+		},
+		{
+			name:        "path only searches PATH",
+			gotoolchain: "path",
+			local:       "go1.21.0",
+			mod:         "go 1.21.5",
+			want:        "go1.21.5",
+			pathOnly:    true,
+		},
+		{
+			name:        "no go.mod",
+			gotoolchain: "auto",
+			local:       "go1.21.0",
+			want:        "go1.21.0",
+			wantLocal:   true,
+		},
+		{
+			name:        "minimum is local",
+			gotoolchain: "go1.21.0+auto",
+			local:       "go1.21.0",
+			mod:         "go 1.20",
+			want:        "go1.21.0",
+			wantLocal:   true,
+		},
+	} {
+		t.Run(td.name, func(t *testing.T) {
+			g, err := ParseGotoolchain(td.gotoolchain)
+			require.NoError(t, err)
+			var mod *ModFile
+			if td.mod != "" {
+				mod, err = ParseModFile([]byte(td.mod))
+				require.NoError(t, err)
+			}
+			got := g.Select(mustVersion(t, td.local), mod)
+			wantVersion := td.wantVersion
+			if wantVersion == "" {
+				wantVersion = td.want
+			}
+			assert.Equal(t, td.want, got.Name)
+			assert.Equal(t, wantVersion, got.Version.String())
+			assert.Equal(t, td.wantLocal, got.Local)
+			assert.Equal(t, td.pathOnly, got.PathOnly)
+		})
+	}
+}
@@ -2,24 +2,22 @@ package goversion
 
 import (
 	"fmt"
+	"math"
 	"regexp"
+	"strconv"
 	"strings"
 	"sync"
 
 	"github.com/Masterminds/semver/v3"
 )
 
-var (
-	versionRegexp    *regexp.Regexp
-	constraintRegexp *regexp.Regexp
-)
+var constraintRegexp *regexp.Regexp
 
 var initRegexpOnce sync.Once
 
 func initRegexp() {
 	initRegexpOnce.Do(func() {
-		constraintRegexp = regexp.MustCompile(`(\A|[\s|])([><=~^][ ><=~^]*)?(x|X|\*|\d+)(?:\.(x|X|\*|\d+))?(?:\.(x|X|\*|\d+))?([[:alpha:]])?`)
-		versionRegexp = regexp.MustCompile(`^go(\d+)(?:\.(\d+))?(?:\.(\d+))?([[:alnum:]]+)?$`)
+		constraintRegexp = regexp.MustCompile(`(\A|[\s|])([><=~^][ ><=~^]*)?(x|X|\*|(?:go)?\d+)(?:\.(x|X|\*|\d+))?(?:\.(x|X|\*|\d+))?([[:alpha:]])?`)
 	})
 }
 
@@ -29,86 +27,255 @@ var ErrInvalidGoVersion = fmt.Errorf("invalid go version")
 // ErrInvalidConstraint is returned when a constraint is not valid
 var ErrInvalidConstraint = fmt.Errorf("invalid go constraint")
 
-// go2semverString returns the semver equivalent of version
+// go2semverString returns the semver equivalent of version or an empty string
+// if version isn't valid.
 func go2semverString(version string) string {
-	initRegexp()
-	if !strings.HasPrefix(version, "go") {
-		version = "go" + version
-	}
-	parts := versionRegexp.FindStringSubmatch(version)
-	if len(parts) == 0 {
+	v, ok := parseVersion(version)
+	if !ok {
 		return ""
 	}
-	for i := 1; i < 4; i++ {
-		if parts[i] == "" {
-			parts[i] = "0"
-		}
-	}
-	sv := fmt.Sprintf("%s.%s.%s", parts[1], parts[2], parts[3])
-	if parts[4] != "" {
-		sv += "-" + parts[4]
-	}
-	return sv
+	return semverString(v.major, v.minor, v.patch, v.prerelease)
 }
 
 // Version represents a single go version
 type Version struct {
-	semver   *semver.Version
-	original string
+	major, minor, patch uint64
+	prerelease          string
+	noMinor             bool
+	noPatch             bool
+	devel               bool
 }
 
 // NewVersion parses a given version and returns an instance of Version or
-// an error if unable to parse the version.
+// an error if unable to parse the version. The error is a *ParseError.
 func NewVersion(version string) (*Version, error) {
-	sv, err := semver.StrictNewVersion(go2semverString(version))
-	if err != nil {
-		return nil, ErrInvalidGoVersion
+	v, ok := parseVersion(version)
+	if !ok {
+		return nil, newVersionError(version)
+	}
+	return &v, nil
+}
+
+// ParseVersion is like NewVersion but returns a Version value. It doesn't
+// allocate when version is valid.
+func ParseVersion(version string) (Version, error) {
+	v, ok := parseVersion(version)
+	if !ok {
+		return Version{}, newVersionError(version)
+	}
+	return v, nil
+}
+
+// parseVersion parses versions like "go1.21.0", "go1.21rc1" or "1.20". The
+// major, minor and patch may not have leading zeros, and the prerelease is
+// any run of ASCII letters and digits. A "devel " prefix marks a development
+// build like "devel go1.23".
+func parseVersion(version string) (Version, bool) {
+	v := Version{
+		noMinor: true,
+		noPatch: true,
+	}
+	s := version
+	if strings.HasPrefix(s, "devel go") {
+		v.devel = true
+		s = s[len("devel "):]
+	}
+	s = strings.TrimPrefix(s, "go")
+	var ok bool
+	v.major, s, ok = parseVersionNumber(s)
+	if !ok {
+		return Version{}, false
+	}
+	if s != "" && s[0] == '.' {
+		v.minor, s, ok = parseVersionNumber(s[1:])
+		if !ok {
+			return Version{}, false
+		}
+		v.noMinor = false
+		if s != "" && s[0] == '.' {
+			v.patch, s, ok = parseVersionNumber(s[1:])
+			if !ok {
+				return Version{}, false
+			}
+			v.noPatch = false
+		}
+	}
+	for i := 0; i < len(s); i++ {
+		if !isAlnum(s[i]) {
+			return Version{}, false
+		}
+	}
+	if v.devel && s != "" {
+		return Version{}, false
+	}
+	v.prerelease = s
+	return v, true
+}
+
+// parseVersionNumber parses the leading digits of s and returns the rest.
+func parseVersionNumber(s string) (n uint64, rest string, ok bool) {
+	i := 0
+	for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+		d := uint64(s[i] - '0')
+		if n > (math.MaxUint64-d)/10 {
+			return 0, "", false
+		}
+		n = n*10 + d
+		i++
 	}
+	if i == 0 || i > 1 && s[0] == '0' {
+		return 0, "", false
+	}
+	return n, s[i:], true
+}
+
+// buildVersion returns a Version from its components.
+func buildVersion(major, minor, patch uint64, prerelease string, noMinor, noPatch bool) *Version {
 	return &Version{
-		semver:   sv,
-		original: version,
-	}, nil
+		major:      major,
+		minor:      minor,
+		patch:      patch,
+		prerelease: prerelease,
+		noMinor:    noMinor,
+		noPatch:    noPatch,
+	}
+}
+
+func semverString(major, minor, patch uint64, prerelease string) string {
+	sv := fmt.Sprintf("%d.%d.%d", major, minor, patch)
+	if prerelease != "" {
+		sv += "-" + prerelease
+	}
+	return sv
+}
+
+// key returns the parts of v that are relevant to ordering. A devel build
+// sorts before every other version with the same numbers and is treated as a
+// prerelease.
+func (v *Version) key() versionKey {
+	if v.devel {
+		return versionKey{
+			major:      v.major,
+			minor:      v.minor,
+			patch:      v.patch,
+			noPatch:    v.noPatch,
+			floor:      true,
+			prerelease: "devel",
+		}
+	}
+	return versionKey{
+		major:      v.major,
+		minor:      v.minor,
+		patch:      v.patch,
+		noPatch:    v.noPatch,
+		prerelease: v.prerelease,
+	}
+}
+
+// Compare returns -1, 0 or 1 depending on whether v is less than, equal to or
+// greater than o using SemverOrdering.
+func (v *Version) Compare(o *Version) int {
+	if v.devel || o.devel {
+		return compareSemverKeys(v.key(), o.key())
+	}
+	if c := compareUint(v.major, o.major); c != 0 {
+		return c
+	}
+	if c := compareUint(v.minor, o.minor); c != 0 {
+		return c
+	}
+	if c := compareUint(v.patch, o.patch); c != 0 {
+		return c
+	}
+	switch {
+	case v.prerelease == o.prerelease:
+		return 0
+	case v.prerelease == "":
+		return 1
+	case o.prerelease == "":
+		return -1
+	default:
+		return comparePrerelease(v.prerelease, o.prerelease)
+	}
 }
 
 // LessThan tests if v is less than o.
 func (v *Version) LessThan(o *Version) bool {
-	return v.semver.LessThan(o.semver)
+	return v.Compare(o) < 0
 }
 
 // GreaterThan tests if v is less than o.
 func (v *Version) GreaterThan(o *Version) bool {
-	return v.semver.GreaterThan(o.semver)
+	return v.Compare(o) > 0
 }
 
-// Equal tests if v is equal to o.
+// Equal tests if v is equal to o. Two nil versions are equal.
 func (v *Version) Equal(o *Version) bool {
-	return v.semver.Equal(o.semver)
+	if v == nil || o == nil {
+		return v == o
+	}
+	return v.Compare(o) == 0
 }
 
 // IsStable returns true if the version is stable meaning it has no prerelease
+// and isn't a devel build.
 func (v *Version) IsStable() bool {
-	return v.semver.Prerelease() == ""
+	return v.prerelease == "" && !v.devel
+}
+
+// IsDevel returns true if v is a development build like "devel go1.23". A
+// devel build sorts after every release and prerelease of earlier versions and
+// before every other version with the same numbers, so
+// go1.22.5 < devel go1.23 < go1.23rc1. Constraints treat devel builds as
+// prereleases.
+func (v *Version) IsDevel() bool {
+	return v.devel
 }
 
-// String returns the string representation of this version.
+// String returns the string representation of this version. Components that
+// were omitted when parsing are omitted here too, so go1.21 and go1.21.0
+// round-trip faithfully.
 func (v Version) String() string {
-	sv := v.semver
-	if sv.Patch() != 0 {
-		return fmt.Sprintf("go%d.%d.%d%s", sv.Major(), sv.Minor(), sv.Patch(), sv.Prerelease())
+	b := make([]byte, 0, 22+len(v.prerelease))
+	if v.devel {
+		b = append(b, "devel "...)
 	}
-	if sv.Minor() != 0 {
-		return fmt.Sprintf("go%d.%d%s", sv.Major(), sv.Minor(), sv.Prerelease())
+	b = append(b, "go"...)
+	b = strconv.AppendUint(b, v.major, 10)
+	if !v.noMinor {
+		b = append(b, '.')
+		b = strconv.AppendUint(b, v.minor, 10)
 	}
-	return fmt.Sprintf("go%d%s", sv.Major(), sv.Prerelease())
+	if !v.noPatch {
+		b = append(b, '.')
+		b = strconv.AppendUint(b, v.patch, 10)
+	}
+	return string(append(b, v.prerelease...))
 }
 
 func go2SemverRange(goRange string) string {
+	semverRange, _ := expandGoRange(goRange)
+	return semverRange
+}
+
+// expandGoRange converts goRange to a semver range. noPatchAt holds the offsets
+// in semverRange of versions that had their patch filled in with a zero.
+func expandGoRange(goRange string) (semverRange string, noPatchAt map[int]bool) {
 	initRegexp()
-	return constraintRegexp.ReplaceAllStringFunc(goRange, func(s string) string {
-		sm := constraintRegexp.FindStringSubmatch(s)
-		if len(sm) < 7 {
-			return sm[0]
+	noPatchAt = map[int]bool{}
+	var sb strings.Builder
+	last := 0
+	for _, idx := range constraintRegexp.FindAllStringSubmatchIndex(goRange, -1) {
+		sb.WriteString(goRange[last:idx[0]])
+		last = idx[1]
+		sm := make([]string, len(idx)/2)
+		for i := range sm {
+			if idx[2*i] >= 0 {
+				sm[i] = goRange[idx[2*i]:idx[2*i+1]]
+			}
 		}
+		// versions may have the go prefix like ">=go1.21"
+		sm[3] = strings.TrimPrefix(sm[3], "go")
 		stopZeros := strings.ContainsAny(sm[3], `Xx*`)
 		if !stopZeros {
 			if sm[4] == "" {
@@ -119,6 +286,7 @@ func go2SemverRange(goRange string) string {
 		if !stopZeros {
 			if sm[5] == "" {
 				sm[5] = "0"
+				noPatchAt[sb.Len()+len(sm[1])+len(sm[2])] = true
 			}
 		}
 		if sm[4] != "" {
@@ -127,39 +295,122 @@ func go2SemverRange(goRange string) string {
 		if sm[5] != "" {
 			sm[5] = "." + sm[5]
 		}
-		result := strings.Join(sm[1:6], "")
+		sb.WriteString(strings.Join(sm[1:6], ""))
 		if sm[6] != "" {
-			result += "-" + sm[6]
+			sb.WriteString("-" + sm[6])
 		}
-		return result
-	})
+	}
+	sb.WriteString(goRange[last:])
+	return sb.String(), noPatchAt
+}
+
+// ConstraintsOptions are options for NewConstraintsWithOptions
+type ConstraintsOptions struct {
+	// Ordering determines how versions are compared to the versions in the
+	// constraint. The default is SemverOrdering.
+	Ordering Ordering
+
+	// Prereleases determines which prerelease versions match. The default is
+	// PrereleaseDefault.
+	Prereleases PrereleasePolicy
 }
 
 // NewConstraints returns a Constraints instance that a Version instance can
-// be checked against.
+// be checked against. The error is a *ParseError.
 func NewConstraints(c string) (*Constraints, error) {
-	semverRange := go2SemverRange(c)
-	constraints, err := semver.NewConstraint(semverRange)
+	return NewConstraintsWithOptions(c, nil)
+}
+
+// NewConstraintsWithOptions is like NewConstraints but accepts options.
+func NewConstraintsWithOptions(c string, options *ConstraintsOptions) (*Constraints, error) {
+	constraints, err := parseConstraints(c, options)
 	if err != nil {
-		return nil, ErrInvalidConstraint
+		return nil, newConstraintError(c)
+	}
+	return constraints, nil
+}
+
+func parseConstraints(c string, options *ConstraintsOptions) (*Constraints, error) {
+	if options == nil {
+		options = new(ConstraintsOptions)
+	}
+	semverRange, noPatchAt := expandGoRange(c)
+	// the semver package is the reference for which ranges are valid
+	_, err := semver.NewConstraint(semverRange)
+	if err != nil {
+		return nil, err
+	}
+	groups, err := parseComparators(semverRange, noPatchAt)
+	if err != nil {
+		return nil, err
 	}
 	return &Constraints{
-		constraints: constraints,
+		original:    c,
+		groups:      groups,
+		ordering:    options.Ordering,
+		prereleases: options.Prereleases,
 	}, nil
 }
 
 // Constraints is one of more constraint that a go version can be checked against.
 type Constraints struct {
-	constraints *semver.Constraints
+	original    string
+	groups      [][]*comparator
+	ordering    Ordering
+	prereleases PrereleasePolicy
+}
+
+// Ordering returns the Ordering used to check versions against c.
+func (c Constraints) Ordering() Ordering {
+	return c.ordering
+}
+
+// Prereleases returns the PrereleasePolicy used to check versions against c.
+func (c Constraints) Prereleases() PrereleasePolicy {
+	return c.prereleases
 }
 
-// Check tests if v satisfies the constraints.
+// options returns the options c was created with.
+func (c Constraints) options() *ConstraintsOptions {
+	return &ConstraintsOptions{
+		Ordering:    c.ordering,
+		Prereleases: c.prereleases,
+	}
+}
+
+// Check tests if v satisfies the constraints. With PrereleaseFallback, v is
+// the only candidate, so a prerelease matches when it would match under
+// PrereleaseInclude. Use FilterVersions to apply the fallback across versions.
 func (c Constraints) Check(v *Version) bool {
-	return c.constraints.Check(v.semver)
+	if c.prereleases == PrereleaseFallback {
+		c.prereleases = c.fallbackPolicy([]*Version{v})
+	}
+	k := v.key()
+	if c.prereleases == PrereleaseOnly && k.prerelease == "" {
+		return false
+	}
+	for _, group := range c.groups {
+		ok := true
+		for _, cmp := range group {
+			if !cmp.check(c.ordering, k, c.excludesPrereleases(cmp)) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return true
+		}
+	}
+	return false
 }
 
-// FilterVersions returns a slice of Versions that satisfy Constraints
+// FilterVersions returns a slice of Versions that satisfy Constraints. With
+// PrereleaseFallback, prereleases are included when no release in versions
+// satisfies c.
 func (c Constraints) FilterVersions(versions []*Version) []*Version {
+	if c.prereleases == PrereleaseFallback {
+		c.prereleases = c.fallbackPolicy(versions)
+	}
 	result := make([]*Version, 0, len(versions))
 	for _, version := range versions {
 		if c.Check(version) {
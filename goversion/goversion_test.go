@@ -1,8 +1,11 @@
 package goversion
 
 import (
+	"errors"
 	"testing"
 
+	"github.com/Masterminds/semver/v3"
+
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -34,6 +37,16 @@ func TestNewVersion(t *testing.T) {
 		{
 			input: "go1.15",
 		},
+		{
+			input: "go1.21.0",
+		},
+		{
+			input: "go1.0",
+		},
+		{
+			input: "1.16.0",
+			want:  "go1.16.0",
+		},
 		{
 			input:   "go1.15.x",
 			wantErr: true,
@@ -80,15 +93,24 @@ func TestNewConstraints(t *testing.T) {
 		{"^1.2beta1", "^1.2.0-beta1"},
 		{"asdf", ""},
 		{"1.x", "1.x"},
+		{"1.21.0-rc1", "1.21.0-rc1"},
+		{"1.21.0-1", ""},
+		{">=1.21.0-0", ""},
+		{"1.21.0-rc.1.2", ""},
+		{"1.21.0--rc", ""},
+		{"1.21.0--", ""},
+		{"0--", ""},
 	} {
 		t.Run(td.s, func(t *testing.T) {
 			got, err := NewConstraints(td.s)
 			if td.want == "" {
-				require.EqualError(t, err, ErrInvalidConstraint.Error())
+				require.True(t, errors.Is(err, ErrInvalidConstraint))
+				var pe *ParseError
+				require.True(t, errors.As(err, &pe))
 				return
 			}
 			require.NoError(t, err)
-			require.Equal(t, td.want, got.constraints.String())
+			require.Equal(t, td.want, got.SemverString())
 		})
 	}
 }
@@ -96,3 +118,34 @@ func TestNewConstraints(t *testing.T) {
 func Test_go2semverRange(t *testing.T) {
 	require.Equal(t, "1.2.0-beta1", go2SemverRange("1.2beta1"))
 }
+
+// compatibilityConstraints and compatibilityVersions cover every constraint
+// form the semver package accepts.
+var (
+	compatibilityConstraints = []string{
+		"1.2beta1", "^1.2beta1", "1.x", "1.15", "1.15.x", "~1.15", "~1.15.2", "^1.15", "^1",
+		">1.15", ">1.15.x", ">1.x", ">=1.15", ">=1.15.x", "<1.15", "<1.15.x", "<=1.15", "<=1.15.x", "<=1.x",
+		"!=1.15", "!= 1.15", "!=1.15.x", "!=1.x", "*", "x", "~*", ">*", "<=*", "!=*",
+		"1.14 - 1.16", ">=1.13 <1.15 || 1.16.x", ">= 1.15, < 1.16", "1.16rc1", ">=1.16beta1", "~1.16rc1",
+		"!= 1.16rc1", "1.14.x || 1.15.x", "<=1.9.2rc2", ">1.9.2rc2 <1.10",
+	}
+	compatibilityVersions = []string{
+		"go1", "go1.2", "go1.2beta1", "go1.2rc1", "go1.9.2rc2", "go1.9.2", "go1.9.3", "go1.13", "go1.14rc1",
+		"go1.14", "go1.14.3", "go1.15beta1", "go1.15", "go1.15.1", "go1.15.2", "go1.15.3", "go1.16beta1",
+		"go1.16rc1", "go1.16", "go1.16.7", "go1.17", "go2", "go2rc1",
+	}
+)
+
+func TestConstraints_Check_semverCompatibility(t *testing.T) {
+	for _, cs := range compatibilityConstraints {
+		sc, err := semver.NewConstraint(go2SemverRange(cs))
+		require.NoError(t, err)
+		c, err := NewConstraints(cs)
+		require.NoError(t, err)
+		for _, vs := range compatibilityVersions {
+			v, err := NewVersion(vs)
+			require.NoError(t, err)
+			assert.Equalf(t, sc.Check(semver.MustParse(go2semverString(vs))), c.Check(v), "constraint %q version %q", cs, vs)
+		}
+	}
+}
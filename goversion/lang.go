@@ -0,0 +1,103 @@
+package goversion
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Lang is a go language version such as go1.21. A language version only has a
+// major and minor component. It is distinct from the toolchain releases that
+// implement it like go1.21.0 and go1.21.1.
+type Lang struct {
+	major, minor uint64
+}
+
+// NewLang parses a language version like "go1.21" or "1.21". Versions with a
+// patch or prerelease are not language versions and return an error.
+func NewLang(lang string) (Lang, error) {
+	v, err := NewVersion(lang)
+	if err != nil {
+		return Lang{}, err
+	}
+	if !v.IsLang() {
+		return Lang{}, &ParseError{
+			Input:      lang,
+			Pos:        -1,
+			Suggestion: fmt.Sprintf("%s is not a language version; did you mean %s?", v, v.Lang()),
+			err:        ErrInvalidGoVersion,
+		}
+	}
+	return v.Lang(), nil
+}
+
+// String returns the language version in the form "go1.21".
+func (l Lang) String() string {
+	return fmt.Sprintf("go%d.%d", l.major, l.minor)
+}
+
+// Compare returns -1, 0 or 1 depending on whether l is less than, equal to or
+// greater than o.
+func (l Lang) Compare(o Lang) int {
+	if c := compareUint(l.major, o.major); c != 0 {
+		return c
+	}
+	return compareUint(l.minor, o.minor)
+}
+
+// Version returns the language version as a Version. Under GoverOrdering it
+// sorts before every prerelease and release of l.
+func (l Lang) Version() *Version {
+	return buildVersion(l.major, l.minor, 0, "", false, true)
+}
+
+// FirstRelease returns the name of the first stable release of l. Starting
+// with Go 1.21 that is the .0 patch release (go1.21.0). Earlier releases were
+// named for the language version (go1.20).
+func (l Lang) FirstRelease() *Version {
+	switch {
+	case l.minor == 0:
+		return buildVersion(l.major, 0, 0, "", true, true)
+	case l.minor >= 21:
+		return buildVersion(l.major, l.minor, 0, "", false, false)
+	default:
+		return buildVersion(l.major, l.minor, 0, "", false, true)
+	}
+}
+
+// Contains tests if v is a prerelease or release of l. A Version that is
+// itself the language version is not contained unless it was released under
+// that name, as releases before Go 1.21 were.
+func (l Lang) Contains(v *Version) bool {
+	if v.Lang() != l {
+		return false
+	}
+	return !v.IsLang() || compareGoverKeys(v.key(), l.FirstRelease().key()) == 0
+}
+
+// Releases returns the stable releases of l found in versions in ascending
+// order.
+func (l Lang) Releases(versions []*Version) []*Version {
+	var result []*Version
+	for _, v := range versions {
+		if v.IsStable() && l.Contains(v) {
+			result = append(result, v)
+		}
+	}
+	sort.Sort(Collection(result))
+	return result
+}
+
+// Lang returns the language version that v belongs to.
+func (v *Version) Lang() Lang {
+	return Lang{
+		major: v.major,
+		minor: v.minor,
+	}
+}
+
+// IsLang returns true if v is written as a language version meaning it has a
+// major and minor but no patch or prerelease. Before Go 1.21 releases were
+// named this way too, so go1.20 is both a language version and a release.
+func (v *Version) IsLang() bool {
+	return !v.noMinor && v.noPatch && v.prerelease == "" && !v.devel
+}
@@ -0,0 +1,98 @@
+package goversion
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewLang(t *testing.T) {
+	for _, td := range []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{input: "go1.21", want: "go1.21"},
+		{input: "1.16", want: "go1.16"},
+		{input: "go1.0", want: "go1.0"},
+		{input: "go1", wantErr: true},
+		{input: "go1.21.0", wantErr: true},
+		{input: "go1.21rc1", wantErr: true},
+		{input: "1.x", wantErr: true},
+	} {
+		t.Run(td.input, func(t *testing.T) {
+			got, err := NewLang(td.input)
+			if td.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, td.want, got.String())
+		})
+	}
+}
+
+func TestVersion_Lang(t *testing.T) {
+	for input, want := range map[string]string{
+		"go1":       "go1.0",
+		"go1.20":    "go1.20",
+		"go1.20rc1": "go1.20",
+		"go1.21":    "go1.21",
+		"go1.21.0":  "go1.21",
+		"go1.21.13": "go1.21",
+	} {
+		assert.Equal(t, want, mustVersion(t, input).Lang().String(), input)
+	}
+	assert.True(t, mustVersion(t, "go1.21").IsLang())
+	assert.True(t, mustVersion(t, "go1.20").IsLang())
+	assert.False(t, mustVersion(t, "go1.21.0").IsLang())
+	assert.False(t, mustVersion(t, "go1.21rc1").IsLang())
+}
+
+func TestLang_FirstRelease(t *testing.T) {
+	for input, want := range map[string]string{
+		"go1.0":  "go1",
+		"go1.20": "go1.20",
+		"go1.21": "go1.21.0",
+		"go1.22": "go1.22.0",
+	} {
+		l, err := NewLang(input)
+		require.NoError(t, err)
+		assert.Equal(t, want, l.FirstRelease().String(), input)
+	}
+}
+
+func TestLang_Version(t *testing.T) {
+	l, err := NewLang("go1.21")
+	require.NoError(t, err)
+	v := l.Version()
+	require.Equal(t, "go1.21", v.String())
+	require.True(t, v.IsLang())
+	require.Equal(t, -1, GoverOrdering.Compare(v, mustVersion(t, "go1.21rc1")))
+}
+
+func TestLang_Releases(t *testing.T) {
+	var versions []*Version
+	for _, s := range []string{
+		"go1.21.1", "go1.20", "go1.21", "go1.21rc1", "go1.20.1", "go1.21.0", "go1.20rc1", "go1.22.0",
+	} {
+		versions = append(versions, mustVersion(t, s))
+	}
+	stringify := func(vs []*Version) []string {
+		result := make([]string, len(vs))
+		for i, v := range vs {
+			result[i] = v.String()
+		}
+		return result
+	}
+	l21, err := NewLang("go1.21")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"go1.21.0", "go1.21.1"}, stringify(l21.Releases(versions)))
+	l20, err := NewLang("go1.20")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"go1.20", "go1.20.1"}, stringify(l20.Releases(versions)))
+	assert.True(t, l21.Contains(mustVersion(t, "go1.21rc1")))
+	assert.False(t, l21.Contains(mustVersion(t, "go1.21")))
+	assert.True(t, l20.Contains(mustVersion(t, "go1.20")))
+}
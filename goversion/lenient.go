@@ -0,0 +1,98 @@
+package goversion
+
+import (
+	"strings"
+	"unicode"
+)
+
+// ParseOptions are options for NewVersionWithOptions. Each option accepts a
+// way go versions are commonly written outside of go itself. The zero value
+// parses as strictly as NewVersion.
+type ParseOptions struct {
+	// AllowV accepts a "v" prefix like "v1.21.0".
+	AllowV bool
+
+	// TrimSpace ignores leading and trailing whitespace and whitespace after
+	// the "go" prefix like "go1.21.0 " or "go 1.21".
+	TrimSpace bool
+
+	// IgnoreCase accepts upper case letters like "Go1.21" or "go1.21RC1".
+	IgnoreCase bool
+
+	// SemverPrerelease accepts semver style prereleases like "1.21.0-rc.1" or
+	// "1.21-rc1". A semver prerelease of a .0 release is the go prerelease of
+	// the language version, so "1.21.0-rc.1" is go1.21rc1. Prereleases that
+	// don't start with a letter like "1.21.3-2" aren't go prereleases and stay
+	// invalid.
+	SemverPrerelease bool
+}
+
+// LenientParseOptions returns ParseOptions with every option enabled.
+func LenientParseOptions() *ParseOptions {
+	return &ParseOptions{
+		AllowV:           true,
+		TrimSpace:        true,
+		IgnoreCase:       true,
+		SemverPrerelease: true,
+	}
+}
+
+// NewVersionWithOptions is like NewVersion but accepts options. The error is a
+// *ParseError holding the original version.
+func NewVersionWithOptions(version string, options *ParseOptions) (*Version, error) {
+	if options == nil {
+		options = new(ParseOptions)
+	}
+	v, ok := parseVersion(options.normalize(version))
+	if !ok {
+		return nil, newVersionError(version)
+	}
+	return &v, nil
+}
+
+// normalize rewrites version into the form NewVersion accepts as far as the
+// options allow.
+func (o *ParseOptions) normalize(version string) string {
+	s := version
+	if o.TrimSpace {
+		s = strings.TrimSpace(s)
+	}
+	if o.IgnoreCase {
+		s = strings.ToLower(s)
+	}
+	if strings.HasPrefix(s, "devel ") {
+		return s
+	}
+	switch {
+	case strings.HasPrefix(s, "go"):
+		s = s[2:]
+		if o.TrimSpace {
+			s = strings.TrimLeftFunc(s, unicode.IsSpace)
+		}
+	case o.AllowV && strings.HasPrefix(s, "v"):
+		s = s[1:]
+	}
+	if o.SemverPrerelease {
+		s = goPrerelease(s)
+	}
+	return "go" + s
+}
+
+// goPrerelease rewrites a semver prerelease like "1.21.0-rc.1" to the go form
+// "1.21rc1". Other versions are returned unchanged, including prereleases
+// that don't start with a letter like "1.21.3-2", which would otherwise run
+// into the version number.
+func goPrerelease(s string) string {
+	i := strings.IndexByte(s, '-')
+	if i == -1 {
+		return s
+	}
+	release, prerelease := s[:i], strings.ReplaceAll(s[i+1:], ".", "")
+	if !isGoPrerelease(prerelease) {
+		return s
+	}
+	if strings.Count(release, ".") == 2 {
+		release = strings.TrimSuffix(release, ".0")
+	}
+	return release + prerelease
+}
@@ -0,0 +1,66 @@
+package goversion
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewVersionWithOptions(t *testing.T) {
+	for _, td := range []struct {
+		version string
+		options ParseOptions
+		want    string
+	}{
+		{version: "go1.21.0", want: "go1.21.0"},
+		{version: "v1.21.0", options: ParseOptions{AllowV: true}, want: "go1.21.0"},
+		{version: "v1.16rc1", options: ParseOptions{AllowV: true}, want: "go1.16rc1"},
+		{version: " go1.21.0\n", options: ParseOptions{TrimSpace: true}, want: "go1.21.0"},
+		{version: "go 1.21", options: ParseOptions{TrimSpace: true}, want: "go1.21"},
+		{version: "Go1.21RC1", options: ParseOptions{IgnoreCase: true}, want: "go1.21rc1"},
+		{version: "1.21.0-rc.1", options: ParseOptions{SemverPrerelease: true}, want: "go1.21rc1"},
+		{version: "1.21-rc1", options: ParseOptions{SemverPrerelease: true}, want: "go1.21rc1"},
+		{version: "1.21.1-rc.2", options: ParseOptions{SemverPrerelease: true}, want: "go1.21.1rc2"},
+		{version: "1.21.10-rc1", options: ParseOptions{SemverPrerelease: true}, want: "go1.21.10rc1"},
+		{version: "1.21.0", options: ParseOptions{SemverPrerelease: true}, want: "go1.21.0"},
+		{version: "Go 1.21", options: *LenientParseOptions(), want: "go1.21"},
+		{version: " V1.21.0-RC.1 ", options: *LenientParseOptions(), want: "go1.21rc1"},
+		{version: "devel go1.23", options: *LenientParseOptions(), want: "devel go1.23"},
+	} {
+		t.Run(td.version, func(t *testing.T) {
+			options := td.options
+			got, err := NewVersionWithOptions(td.version, &options)
+			require.NoError(t, err)
+			assert.Equal(t, td.want, got.String())
+		})
+	}
+}
+
+func TestNewVersionWithOptions_errors(t *testing.T) {
+	for _, td := range []struct {
+		version string
+		options *ParseOptions
+	}{
+		{version: "v1.21.0"},
+		{version: "go1.21.0 "},
+		{version: "Go1.21"},
+		{version: "1.21.0-rc.1"},
+		{version: "v1.21.0", options: &ParseOptions{TrimSpace: true, IgnoreCase: true, SemverPrerelease: true}},
+		{version: "go v1.21.0", options: LenientParseOptions()},
+		{version: "1.21.0-", options: LenientParseOptions()},
+		{version: "1.21.0+build", options: LenientParseOptions()},
+		{version: "1.21.0-1", options: LenientParseOptions()},
+		{version: "1.21.3-2", options: LenientParseOptions()},
+		{version: "v1.22.0-0", options: LenientParseOptions()},
+		{version: "1.21.0-1", options: &ParseOptions{SemverPrerelease: true}},
+	} {
+		t.Run(td.version, func(t *testing.T) {
+			_, err := NewVersionWithOptions(td.version, td.options)
+			var parseErr *ParseError
+			require.True(t, errors.As(err, &parseErr))
+			assert.Equal(t, td.version, parseErr.Input)
+		})
+	}
+}
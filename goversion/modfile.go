@@ -0,0 +1,131 @@
+package goversion
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// defaultModGo is the language version the go command assumes for a go.mod
+// file without a go directive.
+const defaultModGo = "go1.16"
+
+// ModFile holds the go version requirements from a go.mod or go.work file.
+type ModFile struct {
+	// Go is the minimum language version from the go directive. It is nil when
+	// the file has no go directive.
+	Go *Version
+
+	// Toolchain is the version of the toolchain from the toolchain directive.
+	// It is nil when the file has no toolchain directive or the directive is
+	// "default".
+	Toolchain *Version
+
+	// ToolchainName is the toolchain name from the toolchain directive like
+	// "go1.21.3" or "go1.21.3-custom". It is empty when Toolchain is nil.
+	ToolchainName string
+
+	// ToolchainDefault is set when the toolchain directive is "default".
+	ToolchainDefault bool
+}
+
+// ReadModFile reads the go and toolchain directives from a go.mod or go.work
+// file.
+func ReadModFile(filename string) (*ModFile, error) {
+	data, err := os.ReadFile(filename) //nolint:gosec // reading user-provided files is the point
+	if err != nil {
+		return nil, err
+	}
+	mf, err := ParseModFile(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return mf, nil
+}
+
+// ParseModFile parses the go and toolchain directives from the contents of a
+// go.mod or go.work file. Everything else in the file is ignored.
+func ParseModFile(data []byte) (*ModFile, error) {
+	var mf ModFile
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := scanner.Text()
+		if i := strings.Index(line, "//"); i != -1 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		switch fields[0] {
+		case "go", "toolchain":
+		default:
+			continue
+		}
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("line %d: usage: %s version", lineNum, fields[0])
+		}
+		var err error
+		if fields[0] == "go" {
+			mf.Go, err = NewVersion("go" + fields[1])
+		} else {
+			err = mf.setToolchain(fields[1])
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid %s version %q: %w", lineNum, fields[0], fields[1], err)
+		}
+	}
+	err := scanner.Err()
+	if err != nil {
+		return nil, err
+	}
+	return &mf, nil
+}
+
+// setToolchain sets the toolchain fields from the name in a toolchain
+// directive like "go1.21.3", "go1.21.3-custom", "gccgo-go1.21.3" or "default".
+func (m *ModFile) setToolchain(name string) error {
+	if name == "default" {
+		m.ToolchainDefault = true
+		return nil
+	}
+	v, ok := toolchainVersion(name)
+	if !ok {
+		e := newVersionError(name)
+		if _, ok := toolchainVersion("go" + name); ok {
+			e.Suggestion = fmt.Sprintf("toolchain names start with go; did you mean go%s?", name)
+		}
+		return e
+	}
+	m.Toolchain, m.ToolchainName = v, name
+	return nil
+}
+
+// Constraints returns Constraints that match toolchains that are at least
+// m.Go using GoverOrdering. When m has no go directive the go command assumes
+// go 1.16, so toolchains that are at least go1.16 match.
+// The toolchain directive is a preference rather than a requirement, so it
+// does not affect the result.
+func (m *ModFile) Constraints() (*Constraints, error) {
+	return m.ConstraintsWithOptions(nil)
+}
+
+// ConstraintsWithOptions is like Constraints but accepts options. The
+// Ordering in options is ignored because go.mod versions are always ordered
+// with GoverOrdering.
+func (m *ModFile) ConstraintsWithOptions(options *ConstraintsOptions) (*Constraints, error) {
+	c := ">=" + defaultModGo
+	if m.Go != nil {
+		c = ">=" + m.Go.String()
+	}
+	opts := ConstraintsOptions{}
+	if options != nil {
+		opts = *options
+	}
+	opts.Ordering = GoverOrdering
+	return NewConstraintsWithOptions(c, &opts)
+}
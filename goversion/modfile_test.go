@@ -0,0 +1,156 @@
+package goversion
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseModFile(t *testing.T) {
+	for _, td := range []struct {
+		name          string
+		data          string
+		wantGo        string
+		wantToolchain string
+		wantName      string
+		wantErr       bool
+	}{
+		{
+			name: "go.mod",
+			data: `module example.com/foo
+
+go 1.21 // language version
+
+toolchain go1.21.3
+
+require (
+	golang.org/x/mod v0.14.0 // go 1.22
+)
+`,
+			wantGo:        "go1.21",
+			wantToolchain: "go1.21.3",
+			wantName:      "go1.21.3",
+		},
+		{
+			name: "go.work",
+			data: `go 1.22.1
+
+use ./foo
+`,
+			wantGo: "go1.22.1",
+		},
+		{
+			name:   "default toolchain",
+			data:   "go 1.21rc2\ntoolchain default\n",
+			wantGo: "go1.21rc2",
+		},
+		{
+			name:          "custom toolchain",
+			data:          "toolchain go1.21.3-custom\n",
+			wantToolchain: "go1.21.3",
+			wantName:      "go1.21.3-custom",
+		},
+		{
+			name:          "gccgo toolchain",
+			data:          "toolchain gccgo-go1.21.3\n",
+			wantToolchain: "go1.21.3",
+			wantName:      "gccgo-go1.21.3",
+		},
+		{
+			name: "no directives",
+			data: "module example.com/foo\n",
+		},
+		{
+			name:    "invalid go",
+			data:    "go 1.x\n",
+			wantErr: true,
+		},
+		{
+			name:    "invalid toolchain",
+			data:    "toolchain 1.21.3\n",
+			wantErr: true,
+		},
+		{
+			name:    "invalid toolchain path",
+			data:    "toolchain go1.21.3/bin\n",
+			wantErr: true,
+		},
+		{
+			name:    "extra fields",
+			data:    "go 1.21 1.22\n",
+			wantErr: true,
+		},
+	} {
+		t.Run(td.name, func(t *testing.T) {
+			got, err := ParseModFile([]byte(td.data))
+			if td.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			if td.wantGo == "" {
+				assert.Nil(t, got.Go)
+			} else {
+				assert.Equal(t, td.wantGo, got.Go.String())
+			}
+			if td.wantToolchain == "" {
+				assert.Nil(t, got.Toolchain)
+			} else {
+				assert.Equal(t, td.wantToolchain, got.Toolchain.String())
+			}
+			assert.Equal(t, td.wantName, got.ToolchainName)
+		})
+	}
+
+	t.Run("invalid go is ErrInvalidGoVersion", func(t *testing.T) {
+		_, err := ParseModFile([]byte("go 1.x\n"))
+		require.True(t, errors.Is(err, ErrInvalidGoVersion))
+	})
+
+	t.Run("default toolchain", func(t *testing.T) {
+		got, err := ParseModFile([]byte("toolchain default\n"))
+		require.NoError(t, err)
+		assert.True(t, got.ToolchainDefault)
+	})
+
+	t.Run("toolchain suggestion", func(t *testing.T) {
+		_, err := ParseModFile([]byte("toolchain 1.21.3\n"))
+		var pe *ParseError
+		require.True(t, errors.As(err, &pe))
+		assert.Equal(t, "toolchain names start with go; did you mean go1.21.3?", pe.Suggestion)
+	})
+}
+
+func TestReadModFile(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "go.mod")
+	err := os.WriteFile(filename, []byte("module example.com/foo\n\ngo 1.21\n"), 0o600)
+	require.NoError(t, err)
+	mf, err := ReadModFile(filename)
+	require.NoError(t, err)
+	c, err := mf.Constraints()
+	require.NoError(t, err)
+	for v, want := range map[string]bool{
+		"go1.20.14": false,
+		"go1.21":    true,
+		"go1.21rc2": false,
+		"go1.21.0":  true,
+		"go1.22.1":  true,
+	} {
+		assert.Equalf(t, want, c.Check(mustVersion(t, v)), "version %s", v)
+	}
+
+	mf, err = ParseModFile([]byte("module example.com/foo\n"))
+	require.NoError(t, err)
+	c, err = mf.Constraints()
+	require.NoError(t, err)
+	assert.Equal(t, ">=go1.16.0", c.String())
+	assert.False(t, c.Check(mustVersion(t, "go1.15.15")))
+	assert.True(t, c.Check(mustVersion(t, "go1.16")))
+
+	_, err = ReadModFile(filepath.Join(t.TempDir(), "missing"))
+	require.Error(t, err)
+}
@@ -0,0 +1,121 @@
+package goversion
+
+// Major returns the major version.
+func (v *Version) Major() uint64 {
+	return v.major
+}
+
+// Minor returns the minor version. It is 0 when v has no minor.
+func (v *Version) Minor() uint64 {
+	return v.minor
+}
+
+// Patch returns the patch version. It is 0 when v has no patch.
+func (v *Version) Patch() uint64 {
+	return v.patch
+}
+
+// Prerelease returns the prerelease like "rc1" or an empty string for
+// releases.
+func (v *Version) Prerelease() string {
+	return v.prerelease
+}
+
+// MinorFloor returns the first release of v's minor version like go1.21.0 or
+// go1.20.
+func (v *Version) MinorFloor() *Version {
+	return v.Lang().FirstRelease()
+}
+
+// NextMinor returns the first release of the minor version after v's.
+func (v *Version) NextMinor() *Version {
+	return Lang{major: v.major, minor: v.minor + 1}.FirstRelease()
+}
+
+// PrevMinor returns the first release of the minor version before v's. It
+// returns nil when v's minor is 0.
+func (v *Version) PrevMinor() *Version {
+	if v.minor == 0 {
+		return nil
+	}
+	return Lang{major: v.major, minor: v.minor - 1}.FirstRelease()
+}
+
+// NextPatch returns the next patch release after v. For a prerelease or a
+// language version that was never released that is the release it precedes,
+// so go1.21rc1 gives go1.21.0 and go1.21.3 gives go1.21.4.
+func (v *Version) NextPatch() *Version {
+	if !v.IsStable() || v.IsLang() && !v.Lang().Contains(v) {
+		return v.patchRelease(v.patch)
+	}
+	return v.patchRelease(v.patch + 1)
+}
+
+// PrevPatch returns the patch release before v's patch, so go1.21.3 and
+// go1.21.3rc1 give go1.21.2. It returns nil when there is no earlier patch of
+// v's minor version.
+func (v *Version) PrevPatch() *Version {
+	if v.noPatch || v.patch == 0 {
+		return nil
+	}
+	return v.patchRelease(v.patch - 1)
+}
+
+// patchRelease returns the release of v's minor version with the given patch.
+// Patch 0 is the minor's first release.
+func (v *Version) patchRelease(patch uint64) *Version {
+	if patch == 0 {
+		return v.MinorFloor()
+	}
+	return buildVersion(v.major, v.minor, patch, "", false, false)
+}
+
+// NextRelease returns the oldest stable version in c that is newer than v or
+// nil if there is none. Versions compare with GoverOrdering, so the release
+// after the language version go1.21 is go1.21.0.
+func (c Collection) NextRelease(v *Version) *Version {
+	return c.nearestRelease(1, func(o *Version) bool {
+		return GoverOrdering.Compare(o, v) > 0
+	})
+}
+
+// PrevRelease returns the newest stable version in c that is older than v or
+// nil if there is none. Versions compare with GoverOrdering.
+func (c Collection) PrevRelease(v *Version) *Version {
+	return c.nearestRelease(-1, func(o *Version) bool {
+		return GoverOrdering.Compare(o, v) < 0
+	})
+}
+
+// NextMinorRelease returns the oldest stable version in c with a newer minor
+// version than v or nil if there is none.
+func (c Collection) NextMinorRelease(v *Version) *Version {
+	return c.nearestRelease(1, func(o *Version) bool {
+		return o.Lang().Compare(v.Lang()) > 0
+	})
+}
+
+// PrevMinorRelease returns the newest stable version in c with an older
+// minor version than v or nil if there is none.
+func (c Collection) PrevMinorRelease(v *Version) *Version {
+	return c.nearestRelease(-1, func(o *Version) bool {
+		return o.Lang().Compare(v.Lang()) < 0
+	})
+}
+
+// nearestRelease returns the stable version in c that matches ok and is
+// nearest to the version ok compares against. direction is 1 when looking for
+// newer versions and -1 for older ones. Language versions like go1.21 that
+// were never released under that name are skipped.
+func (c Collection) nearestRelease(direction int, ok func(*Version) bool) *Version {
+	var result *Version
+	for _, o := range c {
+		if !o.IsStable() || !o.Lang().Contains(o) || !ok(o) {
+			continue
+		}
+		if result == nil || o.Compare(result) == -direction {
+			result = o
+		}
+	}
+	return result
+}
@@ -0,0 +1,127 @@
+package goversion
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func versionString(v *Version) string {
+	if v == nil {
+		return ""
+	}
+	return v.String()
+}
+
+func TestVersion_accessors(t *testing.T) {
+	v := mustVersion(t, "go1.21.3rc2")
+	assert.Equal(t, uint64(1), v.Major())
+	assert.Equal(t, uint64(21), v.Minor())
+	assert.Equal(t, uint64(3), v.Patch())
+	assert.Equal(t, "rc2", v.Prerelease())
+}
+
+func TestVersion_navigation(t *testing.T) {
+	for _, td := range []struct {
+		version    string
+		minorFloor string
+		nextMinor  string
+		prevMinor  string
+		nextPatch  string
+		prevPatch  string
+	}{
+		{
+			version:    "go1.21.3",
+			minorFloor: "go1.21.0",
+			nextMinor:  "go1.22.0",
+			prevMinor:  "go1.20",
+			nextPatch:  "go1.21.4",
+			prevPatch:  "go1.21.2",
+		},
+		{
+			version:    "go1.21.1",
+			minorFloor: "go1.21.0",
+			nextMinor:  "go1.22.0",
+			prevMinor:  "go1.20",
+			nextPatch:  "go1.21.2",
+			prevPatch:  "go1.21.0",
+		},
+		{
+			version:    "go1.21rc1",
+			minorFloor: "go1.21.0",
+			nextMinor:  "go1.22.0",
+			prevMinor:  "go1.20",
+			nextPatch:  "go1.21.0",
+		},
+		{
+			version:    "go1.21",
+			minorFloor: "go1.21.0",
+			nextMinor:  "go1.22.0",
+			prevMinor:  "go1.20",
+			nextPatch:  "go1.21.0",
+		},
+		{
+			version:    "go1.20",
+			minorFloor: "go1.20",
+			nextMinor:  "go1.21.0",
+			prevMinor:  "go1.19",
+			nextPatch:  "go1.20.1",
+		},
+		{
+			version:    "go1.20.1",
+			minorFloor: "go1.20",
+			nextMinor:  "go1.21.0",
+			prevMinor:  "go1.19",
+			nextPatch:  "go1.20.2",
+			prevPatch:  "go1.20",
+		},
+		{
+			version:    "go1.9.2rc2",
+			minorFloor: "go1.9",
+			nextMinor:  "go1.10",
+			prevMinor:  "go1.8",
+			nextPatch:  "go1.9.2",
+			prevPatch:  "go1.9.1",
+		},
+		{
+			version:    "go1",
+			minorFloor: "go1",
+			nextMinor:  "go1.1",
+			nextPatch:  "go1.0.1",
+		},
+	} {
+		t.Run(td.version, func(t *testing.T) {
+			v := mustVersion(t, td.version)
+			assert.Equal(t, td.minorFloor, versionString(v.MinorFloor()), "MinorFloor")
+			assert.Equal(t, td.nextMinor, versionString(v.NextMinor()), "NextMinor")
+			assert.Equal(t, td.prevMinor, versionString(v.PrevMinor()), "PrevMinor")
+			assert.Equal(t, td.nextPatch, versionString(v.NextPatch()), "NextPatch")
+			assert.Equal(t, td.prevPatch, versionString(v.PrevPatch()), "PrevPatch")
+		})
+	}
+}
+
+func TestCollection_navigation(t *testing.T) {
+	var c Collection
+	for _, s := range []string{"go1.22.1", "go1.20.14", "go1.21.0", "go1.22rc1", "go1.21.13", "go1.22.0", "go1.23rc2"} {
+		c = append(c, mustVersion(t, s))
+	}
+	v := mustVersion(t, "go1.21.0")
+	assert.Equal(t, "go1.21.13", versionString(c.NextRelease(v)))
+	assert.Equal(t, "go1.20.14", versionString(c.PrevRelease(v)))
+	assert.Equal(t, "go1.22.0", versionString(c.NextMinorRelease(v)))
+	assert.Equal(t, "go1.20.14", versionString(c.PrevMinorRelease(v)))
+
+	v = mustVersion(t, "go1.22.1")
+	require.Nil(t, c.NextRelease(v))
+	require.Nil(t, c.NextMinorRelease(v))
+	assert.Equal(t, "go1.22.0", versionString(c.PrevRelease(v)))
+	assert.Equal(t, "go1.21.13", versionString(c.PrevMinorRelease(v)))
+
+	c = Collection{mustVersion(t, "go1.20.14"), mustVersion(t, "go1.21"), mustVersion(t, "go1.21.0"), mustVersion(t, "go1.21.1")}
+	assert.Equal(t, "go1.21.0", versionString(c.NextRelease(mustVersion(t, "go1.20.14"))))
+	assert.Equal(t, "go1.21.0", versionString(c.NextRelease(mustVersion(t, "go1.21"))))
+	assert.Equal(t, "go1.20.14", versionString(c.PrevRelease(mustVersion(t, "go1.21.0"))))
+	assert.Equal(t, "go1.21.0", versionString(c.NextMinorRelease(mustVersion(t, "go1.20.14"))))
+}
@@ -0,0 +1,145 @@
+package goversion
+
+import (
+	"fmt"
+	"strings"
+)
+
+// String returns c in canonical syntax like ">=go1.20.0 <go1.22.0". The result
+// is accepted by NewConstraints and matches the same versions. The patch of a
+// release is written out unless c uses GoverOrdering and the version is a
+// language version like go1.21. Prereleases are written as they were given,
+// and wildcards like "1.21.x" have no go prefix. Use Normalize first to also
+// simplify redundant comparators.
+func (c Constraints) String() string {
+	if len(c.groups) == 0 {
+		return "<0.0.0"
+	}
+	branches := make([]string, len(c.groups))
+	for i, group := range c.groups {
+		s := make([]string, len(group))
+		for j, cmp := range group {
+			s[j] = cmp.canonicalString(c.ordering)
+		}
+		branches[i] = strings.Join(s, " ")
+	}
+	return strings.Join(branches, " || ")
+}
+
+// SemverString returns c as the semver range it is expanded to, like
+// ">=1.20.0 <1.22.0".
+func (c Constraints) SemverString() string {
+	return go2SemverRange(c.original)
+}
+
+// Normalize returns Constraints matching the same versions as c with
+// redundant comparators removed. Wildcard, tilde, caret and hyphen ranges are
+// rewritten as ">=" and "<" comparators, and overlapping branches that only
+// match releases are merged. Branches that match prereleases are kept as they
+// are because rewriting their bounds would change which prereleases match.
+// The release branches are the Releases of Normalized.
+func (c Constraints) Normalize() *Constraints {
+	var releases []interval
+	var branches []string
+	seen := map[string]bool{}
+	for _, group := range c.groups {
+		set := c.groupSet(group)
+		if len(set.prereleases) == 0 {
+			releases = append(releases, set.releases...)
+			continue
+		}
+		s := make([]string, len(group))
+		for i, cmp := range group {
+			s[i] = cmp.canonicalString(c.ordering)
+		}
+		branch := strings.Join(s, " ")
+		if !seen[branch] {
+			seen[branch] = true
+			branches = append(branches, branch)
+		}
+	}
+	releases = mergeReleaseGaps(normalizeIntervals(releases, c.ordering), c.ordering)
+	var releaseBranches []string
+	for _, r := range releaseRanges(releases, c.ordering) {
+		releaseBranches = append(releaseBranches, r.String())
+	}
+	branches = append(releaseBranches, branches...)
+	if len(branches) == 0 {
+		// no groups matches nothing and renders as "<0.0.0"
+		return &Constraints{
+			original:    "<0.0.0",
+			ordering:    c.ordering,
+			prereleases: c.prereleases,
+		}
+	}
+	return mustParseConstraints(strings.Join(branches, " || "), c.options())
+}
+
+// releaseRanges returns the releases in intervals as Ranges. Bounds are moved
+// to the nearest release so that no bound names a prerelease.
+func releaseRanges(intervals []interval, o Ordering) []Range {
+	var ranges []Range
+	for _, i := range intervals {
+		if i.hasRelease(o) {
+			ranges = append(ranges, releaseRange(i, o))
+		}
+	}
+	return ranges
+}
+
+// releaseRange returns the Range matching the same releases as i. It must
+// only be called with an interval that has a release.
+func releaseRange(i interval, o Ordering) Range {
+	var r Range
+	first := nextRelease(incl(floorKey(0, 0, 0, 0)), o)
+	lo := first
+	if !i.lo.unbounded {
+		lo = nextRelease(i.lo, o)
+		if o.compareKeys(lo, first) != 0 {
+			r.Min, r.MinInclusive = keyString(lo, o), true
+		}
+	}
+	if i.hi.unbounded {
+		return r
+	}
+	hi := nextRelease(bound{key: i.hi.key, inclusive: !i.hi.inclusive}, o)
+	if o.compareKeys(nextRelease(excl(lo), o), hi) == 0 {
+		// a single release
+		s := keyString(lo, o)
+		return Range{Min: s, MinInclusive: true, Max: s, MaxInclusive: true}
+	}
+	r.Max = keyString(hi, o)
+	return r
+}
+
+// canonicalString returns the comparator in canonical syntax like ">=go1.20.0"
+// or "1.21.x".
+func (c *comparator) canonicalString(o Ordering) string {
+	return comparatorSyntax(c.op, c.canonicalVersion(o))
+}
+
+// canonicalVersion returns the version of the comparator in canonical syntax
+// like "go1.20.0" or "1.21.x".
+func (c *comparator) canonicalVersion(o Ordering) string {
+	if c.dirty {
+		return c.versionString()
+	}
+	return "go" + keyString(c.key(), o)
+}
+
+// keyString returns k as a go version without the go prefix. The patch of a
+// release is omitted only for language versions under GoverOrdering, and the
+// patch of a prerelease is never added.
+func keyString(k versionKey, o Ordering) string {
+	switch {
+	case o == GoverOrdering:
+		k = k.goverKey()
+	case k.prerelease == "":
+		k.noPatch = false
+	}
+	s := fmt.Sprintf("%d.%d", k.major, k.minor)
+	if !k.noPatch {
+		s += fmt.Sprintf(".%d", k.patch)
+	}
+	return s + k.prerelease
+}
@@ -0,0 +1,108 @@
+package goversion
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConstraints_String(t *testing.T) {
+	for _, td := range []struct {
+		c        string
+		ordering Ordering
+		want     string
+	}{
+		{c: ">=1.20 <1.22", want: ">=go1.20.0 <go1.22.0"},
+		{c: "1.20.x || ~1.21rc1", want: "1.20.x || ~go1.21rc1"},
+		{c: "1.2 - 1.4.5", want: ">=go1.2.0 <=go1.4.5"},
+		{c: "!= 1.21, *", want: "!= go1.21.0 *"},
+		{c: ">=1.16beta1", want: ">=go1.16beta1"},
+		{c: ">=1.20 <=1.21", ordering: GoverOrdering, want: ">=go1.20.0 <=go1.21"},
+		{c: "1.21rc1", ordering: GoverOrdering, want: "go1.21rc1"},
+		{c: ">=go1.20.0 <go1.22.0", want: ">=go1.20.0 <go1.22.0"},
+		{c: "go1.21.x || != go1.21rc1", want: "1.21.x || != go1.21rc1"},
+	} {
+		t.Run(td.c, func(t *testing.T) {
+			require.Equal(t, td.want, mustConstraints(t, td.c, td.ordering).String())
+		})
+	}
+}
+
+func TestConstraints_String_roundTrip(t *testing.T) {
+	for _, ordering := range []Ordering{SemverOrdering, GoverOrdering} {
+		for _, cs := range compatibilityConstraints {
+			t.Run(ordering.String()+" "+cs, func(t *testing.T) {
+				c := mustConstraints(t, cs, ordering)
+				got := mustConstraints(t, c.String(), ordering)
+				require.Equal(t, c.String(), got.String())
+				for _, vs := range compatibilityVersions {
+					v := mustVersion(t, vs)
+					require.Equalf(t, c.Check(v), got.Check(v), "version %q", vs)
+				}
+			})
+		}
+	}
+}
+
+func TestConstraints_String_semverPrereleases(t *testing.T) {
+	versions := append([]string{"go1.21rc1", "go1.21rc2", "go1.21.0", "go1.21.1", "go1.21.3", "go1.21.4rc1", "go1.21.30"}, compatibilityVersions...)
+	for _, cs := range []string{"1.21.0-rc1", ">1.21.3-rc2", ">=1.21.0-beta1 <1.21.1 || 1.21.30", "~1.21.0-rc1"} {
+		t.Run(cs, func(t *testing.T) {
+			c := mustConstraints(t, cs, SemverOrdering)
+			reparsed := mustConstraints(t, c.String(), SemverOrdering)
+			normalized := c.Normalize()
+			for _, vs := range versions {
+				v := mustVersion(t, vs)
+				require.Equalf(t, c.Check(v), reparsed.Check(v), "String %q version %q", c.String(), vs)
+				require.Equalf(t, c.Check(v), normalized.Check(v), "Normalize %q version %q", normalized.String(), vs)
+			}
+		})
+	}
+
+	// prereleases no go version has can't be rendered, so they are rejected
+	for _, cs := range []string{"1.21.0-1", ">1.21.3-0", "0--", ">=1.21.0-rc.1"} {
+		t.Run(cs, func(t *testing.T) {
+			_, err := NewConstraints(cs)
+			require.True(t, errors.Is(err, ErrInvalidConstraint))
+		})
+	}
+}
+
+func TestConstraints_Normalize(t *testing.T) {
+	for _, td := range []struct {
+		c        string
+		ordering Ordering
+		want     string
+	}{
+		{c: ">=1.20 <1.22", want: ">=go1.20.0 <go1.22.0"},
+		{c: "1.20.x || 1.21.x", want: ">=go1.20.0 <go1.22.0"},
+		{c: ">=1.19 >=1.20 <1.23 <1.22", want: ">=go1.20.0 <go1.22.0"},
+		{c: "~1.20.3 || 1.20.x", want: ">=go1.20.0 <go1.21.0"},
+		{c: "^1.20", want: ">=go1.20.0 <go2.0.0"},
+		{c: ">1.20.3 <1.20.5", want: "go1.20.4"},
+		{c: ">=1.20 != 1.20.3 <1.21", want: ">=go1.20.0 <go1.20.3 || >=go1.20.4 <go1.21.0"},
+		{c: ">1.20.3 <1.20.4", want: "<0.0.0"},
+		{c: "*", want: "*"},
+		{c: ">=1.21rc1 <1.22", want: ">=go1.21.0 <go1.22.0"},
+		{c: ">=1.21rc1 || 1.21.x", want: ">=go1.21.0 <go1.22.0 || >=go1.21rc1"},
+		{c: "1.21.x", ordering: GoverOrdering, want: ">=go1.21 <go1.22"},
+		{c: ">1.20 <=1.21.0", ordering: GoverOrdering, want: ">=go1.20.1 <go1.21.1"},
+		{c: "<=1.15", want: "<go1.15.1"},
+	} {
+		t.Run(td.c, func(t *testing.T) {
+			c := mustConstraints(t, td.c, td.ordering)
+			got := c.Normalize()
+			require.Equal(t, td.want, got.String())
+			require.Equal(t, c.Normalized().Releases, got.Normalized().Releases)
+			require.True(t, got.IsSubset(c))
+			require.True(t, c.IsSubset(got))
+			require.Equal(t, td.ordering, got.Ordering())
+		})
+	}
+}
+
+func TestConstraints_SemverString(t *testing.T) {
+	c := mustConstraints(t, ">=1.20 <1.22rc1 || 1.15.x", SemverOrdering)
+	require.Equal(t, ">=1.20.0 <1.22.0-rc1 || 1.15.x", c.SemverString())
+}
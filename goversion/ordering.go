@@ -0,0 +1,163 @@
+package goversion
+
+import "strconv"
+
+// Ordering determines how two go versions compare to each other.
+type Ordering int
+
+const (
+	// SemverOrdering compares versions by their semver equivalents. go1.21 and
+	// go1.21.0 are equal, and prereleases sort before the release they precede.
+	// Unlike semver, prereleases compare by PrereleaseKind and then by number,
+	// so go1.21rc2 < go1.21rc10. This is the default ordering.
+	SemverOrdering Ordering = iota
+
+	// GoverOrdering compares versions the same way the go command orders
+	// toolchains. Starting with Go 1.21 a version without a patch is a language
+	// version that sorts before the release candidates for that minor version,
+	// so go1.21 < go1.21rc1 < go1.21.0 < go1.21.1. Before Go 1.21, go1.20 and
+	// go1.20.0 are equal and prereleases sort before them.
+	GoverOrdering
+)
+
+// String returns the name of the ordering.
+func (o Ordering) String() string {
+	switch o {
+	case SemverOrdering:
+		return "semver"
+	case GoverOrdering:
+		return "gover"
+	default:
+		return "Ordering(" + strconv.Itoa(int(o)) + ")"
+	}
+}
+
+// Compare returns -1, 0 or 1 depending on whether a is less than, equal to or
+// greater than b.
+func (o Ordering) Compare(a, b *Version) int {
+	return o.compareKeys(a.key(), b.key())
+}
+
+// Less tests if a is less than b.
+func (o Ordering) Less(a, b *Version) bool {
+	return o.Compare(a, b) < 0
+}
+
+func (o Ordering) compareKeys(a, b versionKey) int {
+	if o == GoverOrdering {
+		return compareGoverKeys(a, b)
+	}
+	return compareSemverKeys(a, b)
+}
+
+// versionKey holds the parts of a version that are relevant to ordering. It is
+// also used for the bounds of constraint intervals, which is why it can
+// represent the floor of a version family.
+type versionKey struct {
+	major, minor, patch uint64
+
+	// noPatch is set when a version has no patch and, under GoverOrdering, is a
+	// language version or a prerelease of a language version.
+	noPatch bool
+
+	// floor is set on bounds that sort before every version with the same
+	// major, minor and patch.
+	floor bool
+
+	prerelease string
+}
+
+// goverKey returns k with the patch normalized the way the go command does.
+// Before Go 1.21 a missing patch on a release means .0.
+func (k versionKey) goverKey() versionKey {
+	if k.noPatch && k.prerelease == "" && !k.floor && k.minor < 21 {
+		k.noPatch = false
+	}
+	return k
+}
+
+func compareUint(a, b uint64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func compareSemverKeys(a, b versionKey) int {
+	if c := compareUint(a.major, b.major); c != 0 {
+		return c
+	}
+	if c := compareUint(a.minor, b.minor); c != 0 {
+		return c
+	}
+	if c := compareUint(a.patch, b.patch); c != 0 {
+		return c
+	}
+	if c := compareFloor(a, b); c != 0 || a.floor {
+		return c
+	}
+	switch {
+	case a.prerelease == b.prerelease:
+		return 0
+	case a.prerelease == "":
+		return 1
+	case b.prerelease == "":
+		return -1
+	default:
+		return comparePrerelease(a.prerelease, b.prerelease)
+	}
+}
+
+func compareGoverKeys(a, b versionKey) int {
+	a, b = a.goverKey(), b.goverKey()
+	if c := compareUint(a.major, b.major); c != 0 {
+		return c
+	}
+	if c := compareUint(a.minor, b.minor); c != 0 {
+		return c
+	}
+	switch {
+	case a.noPatch && !b.noPatch:
+		return -1
+	case !a.noPatch && b.noPatch:
+		return 1
+	}
+	if c := compareUint(a.patch, b.patch); c != 0 {
+		return c
+	}
+	if c := compareFloor(a, b); c != 0 || a.floor {
+		return c
+	}
+	if a.prerelease == b.prerelease {
+		return 0
+	}
+	// A language version sorts before its prereleases, but a release with a
+	// patch sorts after its prereleases (as with go1.9.2rc2 < go1.9.2).
+	releaseRank := -1
+	if !a.noPatch {
+		releaseRank = 1
+	}
+	switch {
+	case a.prerelease == "":
+		return releaseRank
+	case b.prerelease == "":
+		return -releaseRank
+	default:
+		return comparePrerelease(a.prerelease, b.prerelease)
+	}
+}
+
+func compareFloor(a, b versionKey) int {
+	switch {
+	case a.floor == b.floor:
+		return 0
+	case a.floor:
+		return -1
+	default:
+		return 1
+	}
+}
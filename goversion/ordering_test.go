@@ -0,0 +1,115 @@
+package goversion
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mustVersion(t *testing.T, s string) *Version {
+	t.Helper()
+	v, err := NewVersion(s)
+	require.NoError(t, err)
+	return v
+}
+
+func TestOrdering_Compare(t *testing.T) {
+	for _, td := range []struct {
+		a, b          string
+		semver, gover int
+	}{
+		{a: "go1.21", b: "go1.21.0", semver: 0, gover: -1},
+		{a: "go1.21", b: "go1.21rc1", semver: 1, gover: -1},
+		{a: "go1.21rc1", b: "go1.21.0", semver: -1, gover: -1},
+		{a: "go1.21rc1", b: "go1.21rc2", semver: -1, gover: -1},
+		{a: "go1.21rc2", b: "go1.21.0", semver: -1, gover: -1},
+		{a: "go1.21.0", b: "go1.21.1", semver: -1, gover: -1},
+		{a: "go1.21.1", b: "go1.22", semver: -1, gover: -1},
+		{a: "go1.22rc1", b: "go1.21.10", semver: 1, gover: 1},
+		{a: "go1.20", b: "go1.20.0", semver: 0, gover: 0},
+		{a: "go1.20rc1", b: "go1.20", semver: -1, gover: -1},
+		{a: "go1.20beta1", b: "go1.20rc1", semver: -1, gover: -1},
+		{a: "go1.20", b: "go1.20.1", semver: -1, gover: -1},
+		{a: "go1.20.14", b: "go1.21", semver: -1, gover: -1},
+		{a: "go1.9.2rc2", b: "go1.9.2", semver: -1, gover: -1},
+		{a: "go1.9.1", b: "go1.9.2rc2", semver: -1, gover: -1},
+		{a: "go1", b: "go1.0.0", semver: 0, gover: 0},
+		{a: "go1.21beta1", b: "go1.21rc1", semver: -1, gover: -1},
+		{a: "go1.21rc10", b: "go1.21rc2", semver: 1, gover: 1},
+		{a: "go1.21beta2", b: "go1.21rc1", semver: -1, gover: -1},
+		{a: "go1.21alpha3", b: "go1.21beta1", semver: -1, gover: -1},
+		{a: "go1.21rc1", b: "go1.21rc01", semver: 1, gover: 1},
+		{a: "go1.3", b: "go1.21", semver: -1, gover: -1},
+	} {
+		t.Run(td.a+" "+td.b, func(t *testing.T) {
+			a, b := mustVersion(t, td.a), mustVersion(t, td.b)
+			assert.Equal(t, td.semver, SemverOrdering.Compare(a, b), "semver")
+			assert.Equal(t, -td.semver, SemverOrdering.Compare(b, a), "semver reversed")
+			assert.Equal(t, td.gover, GoverOrdering.Compare(a, b), "gover")
+			assert.Equal(t, -td.gover, GoverOrdering.Compare(b, a), "gover reversed")
+		})
+	}
+}
+
+func TestGoverOrdering_sort(t *testing.T) {
+	want := []string{
+		"go1.19rc1", "go1.19", "go1.19.1",
+		"go1.20beta1", "go1.20rc1", "go1.20rc2", "go1.20", "go1.20.1", "go1.20.14",
+		"go1.21", "go1.21rc1", "go1.21rc2", "go1.21.0", "go1.21.1", "go1.21.10",
+		"go1.22", "go1.22rc1", "go1.22.0",
+	}
+	versions := make([]*Version, len(want))
+	for i := range want {
+		versions[len(want)-1-i] = mustVersion(t, want[i])
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		return GoverOrdering.Less(versions[i], versions[j])
+	})
+	got := make([]string, len(versions))
+	for i, v := range versions {
+		got[i] = v.String()
+	}
+	require.Equal(t, want, got)
+}
+
+func TestConstraints_gover(t *testing.T) {
+	for _, td := range []struct {
+		constraint string
+		version    string
+		semver     bool
+		gover      bool
+	}{
+		{constraint: ">=1.21", version: "go1.21", semver: true, gover: true},
+		{constraint: ">=1.21", version: "go1.21.0", semver: true, gover: true},
+		{constraint: ">=1.21rc1", version: "go1.21rc2", semver: true, gover: true},
+		{constraint: ">=1.21rc1", version: "go1.21", semver: true, gover: false},
+		{constraint: "1.21", version: "go1.21.0", semver: true, gover: false},
+		{constraint: "1.21", version: "go1.21", semver: true, gover: true},
+		{constraint: "1.21.0", version: "go1.21", semver: true, gover: false},
+		{constraint: "1.20", version: "go1.20.0", semver: true, gover: true},
+		{constraint: "<=1.21", version: "go1.21.0", semver: true, gover: false},
+		{constraint: "<1.21.0", version: "go1.21", semver: false, gover: true},
+		{constraint: "<1.21.0", version: "go1.20.14", semver: true, gover: true},
+		{constraint: "~1.21", version: "go1.21.5", semver: true, gover: true},
+		{constraint: "~1.21", version: "go1.22", semver: false, gover: false},
+		{constraint: "1.21.x", version: "go1.21", semver: true, gover: true},
+		{constraint: "1.21.x", version: "go1.21.9", semver: true, gover: true},
+		{constraint: "1.21.x", version: "go1.21rc1", semver: false, gover: false},
+		{constraint: "^1.21", version: "go1.22.1", semver: true, gover: true},
+		{constraint: ">1.21", version: "go1.21.0", semver: false, gover: true},
+		{constraint: "!= 1.21", version: "go1.21.0", semver: false, gover: true},
+	} {
+		t.Run(td.constraint+" "+td.version, func(t *testing.T) {
+			v := mustVersion(t, td.version)
+			c, err := NewConstraints(td.constraint)
+			require.NoError(t, err)
+			assert.Equal(t, td.semver, c.Check(v), "semver")
+			c, err = NewConstraintsWithOptions(td.constraint, &ConstraintsOptions{Ordering: GoverOrdering})
+			require.NoError(t, err)
+			assert.Equal(t, GoverOrdering, c.Ordering())
+			assert.Equal(t, td.gover, c.Check(v), "gover")
+		})
+	}
+}
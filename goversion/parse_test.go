@@ -0,0 +1,162 @@
+package goversion
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/stretchr/testify/require"
+)
+
+// legacyVersionRegexp and legacyParseVersion are the regexp and semver based
+// parser that parseVersion replaced. They are kept to check that both agree.
+var legacyVersionRegexp = regexp.MustCompile(`^go(\d+)(?:\.(\d+))?(?:\.(\d+))?([[:alnum:]]+)?$`)
+
+type legacyVersion struct {
+	semver           *semver.Version
+	noMinor, noPatch bool
+}
+
+func legacyParseVersion(version string) (*legacyVersion, bool) {
+	s := version
+	if len(s) < 2 || s[:2] != "go" {
+		s = "go" + s
+	}
+	parts := legacyVersionRegexp.FindStringSubmatch(s)
+	if len(parts) == 0 {
+		return nil, false
+	}
+	noMinor, noPatch := parts[2] == "", parts[3] == ""
+	for i := 1; i < 4; i++ {
+		if parts[i] == "" {
+			parts[i] = "0"
+		}
+	}
+	sv := parts[1] + "." + parts[2] + "." + parts[3]
+	if parts[4] != "" {
+		sv += "-" + parts[4]
+	}
+	v, err := semver.StrictNewVersion(sv)
+	if err != nil {
+		return nil, false
+	}
+	return &legacyVersion{semver: v, noMinor: noMinor, noPatch: noPatch}, true
+}
+
+var parseTestVersions = []string{
+	"go1", "go1.2", "go1.2.3", "go1.21rc1", "go1.21.0", "go1.9.2rc2", "go2beta1",
+	"1.16", "1.16.15", "go1.02", "go01", "go1.2.3.4", "go1.", "go", "", "go1.2-rc1",
+	"gogo1", "go18446744073709551615", "go18446744073709551616", "go1.2.3rc01",
+	"go1.21.0-bigcorp", "go1rc", "go1.2a", "go1.2.3A1b2",
+}
+
+func FuzzParseVersion(f *testing.F) {
+	for _, s := range parseTestVersions {
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		if strings.HasPrefix(s, "devel go") {
+			// devel builds weren't supported by the legacy parser
+			return
+		}
+		got, ok := parseVersion(s)
+		want, wantOK := legacyParseVersion(s)
+		require.Equal(t, wantOK, ok, "%q", s)
+		if !ok {
+			return
+		}
+		require.Equal(t, want.semver.Major(), got.major)
+		require.Equal(t, want.semver.Minor(), got.minor)
+		require.Equal(t, want.semver.Patch(), got.patch)
+		require.Equal(t, want.semver.Prerelease(), got.prerelease)
+		require.Equal(t, want.noMinor, got.noMinor)
+		require.Equal(t, want.noPatch, got.noPatch)
+	})
+}
+
+func FuzzVersion_Compare(f *testing.F) {
+	for _, a := range parseTestVersions {
+		f.Add(a, "go1.21rc1")
+	}
+	f.Fuzz(func(t *testing.T, a, b string) {
+		av, aOK := parseVersion(a)
+		bv, bOK := parseVersion(b)
+		if !aOK || !bOK || av.devel || bv.devel {
+			return
+		}
+		la, _ := legacyParseVersion(a)
+		lb, _ := legacyParseVersion(b)
+		want := la.semver.Compare(lb.semver)
+		ra, _ := la.semver.SetPrerelease("")
+		rb, _ := lb.semver.SetPrerelease("")
+		if av.prerelease != "" && bv.prerelease != "" && ra.Equal(&rb) {
+			// prereleases compare by kind and number instead of lexically
+			want = comparePrerelease(av.prerelease, bv.prerelease)
+		}
+		require.Equal(t, want, av.Compare(&bv), "%q %q", a, b)
+	})
+}
+
+func TestParseVersion(t *testing.T) {
+	v, err := ParseVersion("go1.21rc1")
+	require.NoError(t, err)
+	require.Equal(t, "go1.21rc1", v.String())
+	_, err = ParseVersion("go1.02")
+	require.EqualError(t, err, `invalid go version "go1.02": unexpected "02" at position 4`)
+	allocs := testing.AllocsPerRun(100, func() {
+		_, err = ParseVersion("go1.21.0rc1")
+	})
+	require.Zero(t, allocs)
+}
+
+func BenchmarkParseVersion(b *testing.B) {
+	b.Run("native", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_, ok := parseVersion(parseTestVersions[i%len(parseTestVersions)])
+			_ = ok
+		}
+	})
+	b.Run("legacy", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_, ok := legacyParseVersion(parseTestVersions[i%len(parseTestVersions)])
+			_ = ok
+		}
+	})
+}
+
+func BenchmarkVersion_sort(b *testing.B) {
+	var native []*Version
+	var legacy []*semver.Version
+	for _, s := range parseTestVersions {
+		if v, ok := parseVersion(s); ok {
+			native = append(native, &v)
+		}
+		if v, ok := legacyParseVersion(s); ok {
+			legacy = append(legacy, v.semver)
+		}
+	}
+	b.Run("native", func(b *testing.B) {
+		b.ReportAllocs()
+		versions := make([]*Version, len(native))
+		for i := 0; i < b.N; i++ {
+			copy(versions, native)
+			sort.Slice(versions, func(i, j int) bool {
+				return versions[i].LessThan(versions[j])
+			})
+		}
+	})
+	b.Run("legacy", func(b *testing.B) {
+		b.ReportAllocs()
+		versions := make([]*semver.Version, len(legacy))
+		for i := 0; i < b.N; i++ {
+			copy(versions, legacy)
+			sort.Slice(versions, func(i, j int) bool {
+				return versions[i].LessThan(versions[j])
+			})
+		}
+	})
+}
@@ -0,0 +1,193 @@
+package goversion
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// PrereleasePolicy determines which prerelease versions Constraints match.
+type PrereleasePolicy int
+
+const (
+	// PrereleaseDefault matches a prerelease only when every comparator in a
+	// branch names a prerelease, so "1.22.x" doesn't match go1.22rc1 but
+	// ">=1.22rc1" does. This is how semver constraints behave.
+	PrereleaseDefault PrereleasePolicy = iota
+
+	// PrereleaseExclude never matches prereleases.
+	PrereleaseExclude
+
+	// PrereleaseInclude matches prereleases the same way it matches releases,
+	// so "1.22.x" matches go1.22rc1.
+	PrereleaseInclude
+
+	// PrereleaseOnly is like PrereleaseInclude but never matches releases.
+	PrereleaseOnly
+
+	// PrereleaseFallback is decided by a set of candidate versions. It behaves
+	// like PrereleaseDefault when a release in the set matches and like
+	// PrereleaseInclude otherwise. FilterVersions and ExplainIn use the
+	// versions they are given, Check and Explain use only the version being
+	// checked, and Satisfiable, IsSubset and Normalized use every possible
+	// version.
+	PrereleaseFallback
+)
+
+// ErrInvalidPrereleasePolicy is returned when a prerelease policy name isn't recognized
+var ErrInvalidPrereleasePolicy = fmt.Errorf("invalid prerelease policy")
+
+var prereleasePolicyNames = []string{"default", "exclude", "include", "only", "fallback"}
+
+// String returns the name of the policy.
+func (p PrereleasePolicy) String() string {
+	if p >= 0 && int(p) < len(prereleasePolicyNames) {
+		return prereleasePolicyNames[p]
+	}
+	return "PrereleasePolicy(" + strconv.Itoa(int(p)) + ")"
+}
+
+// ParsePrereleasePolicy returns the policy named s. The names are default,
+// exclude, include, only and fallback. An empty s is PrereleaseDefault.
+func ParsePrereleasePolicy(s string) (PrereleasePolicy, error) {
+	if s == "" {
+		return PrereleaseDefault, nil
+	}
+	for i, name := range prereleasePolicyNames {
+		if strings.EqualFold(s, name) {
+			return PrereleasePolicy(i), nil
+		}
+	}
+	return 0, fmt.Errorf("%w %q: must be one of %s", ErrInvalidPrereleasePolicy, s, strings.Join(prereleasePolicyNames, ", "))
+}
+
+// excludesPrereleases reports whether cmp rejects prereleases under c's
+// PrereleasePolicy.
+func (c Constraints) excludesPrereleases(cmp *comparator) bool {
+	switch c.prereleases {
+	case PrereleaseInclude, PrereleaseOnly:
+		return false
+	case PrereleaseExclude:
+		return true
+	default:
+		return cmp.excludesPrereleases()
+	}
+}
+
+// fallbackPolicy returns the policy PrereleaseFallback resolves to for the
+// candidates in versions. It is PrereleaseDefault when a release in versions
+// satisfies c and PrereleaseInclude otherwise.
+func (c Constraints) fallbackPolicy(versions []*Version) PrereleasePolicy {
+	c.prereleases = PrereleaseDefault
+	for _, v := range versions {
+		if v.IsStable() && c.Check(v) {
+			return PrereleaseDefault
+		}
+	}
+	return PrereleaseInclude
+}
+
+// PrereleaseKind is the kind of a go prerelease. Kinds are ordered the way
+// they occur in the go release process, so a prerelease of one kind sorts
+// before every prerelease of a later kind regardless of their numbers.
+type PrereleaseKind int
+
+const (
+	// PrereleaseKindDevel is the kind of devel builds like "devel go1.23".
+	PrereleaseKindDevel PrereleaseKind = iota
+
+	// PrereleaseKindOther is the kind of prereleases go doesn't name like
+	// "alpha1". They compare by name and then by number.
+	PrereleaseKindOther
+
+	// PrereleaseKindBeta is the kind of beta prereleases like "beta1".
+	PrereleaseKindBeta
+
+	// PrereleaseKindRC is the kind of release candidates like "rc1".
+	PrereleaseKindRC
+
+	// PrereleaseKindRelease is the kind of versions that aren't prereleases.
+	PrereleaseKindRelease
+)
+
+var prereleaseKindNames = []string{"devel", "other", "beta", "rc", "release"}
+
+// String returns the name of the kind.
+func (k PrereleaseKind) String() string {
+	if k >= 0 && int(k) < len(prereleaseKindNames) {
+		return prereleaseKindNames[k]
+	}
+	return "PrereleaseKind(" + strconv.Itoa(int(k)) + ")"
+}
+
+// PrereleaseKind returns the kind of v's prerelease.
+func (v *Version) PrereleaseKind() PrereleaseKind {
+	if v.devel {
+		return PrereleaseKindDevel
+	}
+	kind, _, _ := parsePrerelease(v.prerelease)
+	return kind
+}
+
+// PrereleaseNumber returns the number of v's prerelease like 2 for go1.21rc2.
+// It is 0 when v isn't a prerelease or the prerelease has no number.
+func (v *Version) PrereleaseNumber() uint64 {
+	_, _, num := parsePrerelease(v.prerelease)
+	return num
+}
+
+// parsePrerelease splits a prerelease like "rc2" into its kind, name and
+// number. A prerelease that isn't letters followed by digits is
+// PrereleaseKindOther with the whole prerelease as its name.
+func parsePrerelease(pre string) (kind PrereleaseKind, name string, num uint64) {
+	if pre == "" {
+		return PrereleaseKindRelease, "", 0
+	}
+	name, num, ok := splitPrerelease(pre)
+	if !ok {
+		return PrereleaseKindOther, pre, 0
+	}
+	switch name {
+	case "beta":
+		return PrereleaseKindBeta, name, num
+	case "rc":
+		return PrereleaseKindRC, name, num
+	default:
+		return PrereleaseKindOther, name, num
+	}
+}
+
+// splitPrerelease splits a prerelease like "rc1" into its name and number.
+func splitPrerelease(pre string) (name string, num uint64, ok bool) {
+	i := strings.IndexAny(pre, "0123456789")
+	if i == -1 {
+		return pre, 0, true
+	}
+	num, err := strconv.ParseUint(pre[i:], 10, 64)
+	if err != nil {
+		return "", 0, false
+	}
+	return pre[:i], num, true
+}
+
+// comparePrerelease compares prereleases by kind, then name and then number,
+// so beta2 < rc1 < rc10. Prereleases that only differ in how their number is
+// written, like rc1 and rc01, compare lexically so that only identical
+// prereleases are equal.
+func comparePrerelease(a, b string) int {
+	if a == b {
+		return 0
+	}
+	aKind, aName, aNum := parsePrerelease(a)
+	bKind, bName, bNum := parsePrerelease(b)
+	if c := compareUint(uint64(aKind), uint64(bKind)); c != 0 {
+		return c
+	}
+	if c := strings.Compare(aName, bName); c != 0 {
+		return c
+	}
+	if c := compareUint(aNum, bNum); c != 0 {
+		return c
+	}
+	return strings.Compare(a, b)
+}
@@ -0,0 +1,142 @@
+package goversion
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePrereleasePolicy(t *testing.T) {
+	for _, p := range []PrereleasePolicy{PrereleaseDefault, PrereleaseExclude, PrereleaseInclude, PrereleaseOnly, PrereleaseFallback} {
+		got, err := ParsePrereleasePolicy(p.String())
+		require.NoError(t, err)
+		require.Equal(t, p, got)
+	}
+	got, err := ParsePrereleasePolicy("")
+	require.NoError(t, err)
+	require.Equal(t, PrereleaseDefault, got)
+	_, err = ParsePrereleasePolicy("sometimes")
+	require.True(t, errors.Is(err, ErrInvalidPrereleasePolicy))
+	require.Equal(t, "PrereleasePolicy(9)", PrereleasePolicy(9).String())
+}
+
+func TestConstraints_prereleases(t *testing.T) {
+	versions := []string{"go1.21.5", "go1.22rc1", "go1.22rc2", "go1.22.0", "go1.23rc1"}
+	for _, td := range []struct {
+		c      string
+		policy PrereleasePolicy
+		want   []string
+	}{
+		{c: "1.22.x", policy: PrereleaseDefault, want: []string{"go1.22.0"}},
+		{c: ">=1.22rc2", policy: PrereleaseDefault, want: []string{"go1.22rc2", "go1.22.0", "go1.23rc1"}},
+		{c: ">=1.22rc2", policy: PrereleaseExclude, want: []string{"go1.22.0"}},
+		{c: "1.22.x", policy: PrereleaseInclude, want: []string{"go1.22rc1", "go1.22rc2", "go1.22.0"}},
+		{c: "1.22.x", policy: PrereleaseOnly, want: []string{"go1.22rc1", "go1.22rc2"}},
+		{c: "1.22.x", policy: PrereleaseFallback, want: []string{"go1.22.0"}},
+		{c: "1.23.x", policy: PrereleaseFallback, want: []string{"go1.23rc1"}},
+		{c: "1.23.x", policy: PrereleaseDefault, want: []string{}},
+	} {
+		t.Run(td.policy.String()+" "+td.c, func(t *testing.T) {
+			c, err := NewConstraintsWithOptions(td.c, &ConstraintsOptions{Prereleases: td.policy})
+			require.NoError(t, err)
+			require.Equal(t, td.policy, c.Prereleases())
+			candidates := make([]*Version, len(versions))
+			for i, s := range versions {
+				candidates[i] = mustVersion(t, s)
+			}
+			got := make([]string, 0, len(td.want))
+			for _, v := range c.FilterVersions(candidates) {
+				got = append(got, v.String())
+			}
+			require.Equal(t, td.want, got)
+		})
+	}
+}
+
+func TestConstraints_prereleasesSatisfiable(t *testing.T) {
+	c, err := NewConstraintsWithOptions(">1.22.0 <1.22.1", &ConstraintsOptions{Prereleases: PrereleaseDefault})
+	require.NoError(t, err)
+	assert.False(t, c.Satisfiable())
+	c, err = NewConstraintsWithOptions(">1.22.0 <1.22.1", &ConstraintsOptions{Prereleases: PrereleaseFallback})
+	require.NoError(t, err)
+	assert.True(t, c.Satisfiable())
+	c, err = NewConstraintsWithOptions("1.22.0", &ConstraintsOptions{Prereleases: PrereleaseOnly})
+	require.NoError(t, err)
+	assert.False(t, c.Satisfiable())
+
+	include, err := NewConstraintsWithOptions("1.22.x", &ConstraintsOptions{Prereleases: PrereleaseInclude})
+	require.NoError(t, err)
+	only, err := NewConstraintsWithOptions("1.22.x", &ConstraintsOptions{Prereleases: PrereleaseOnly})
+	require.NoError(t, err)
+	assert.True(t, only.IsSubset(include))
+	assert.False(t, include.IsSubset(only))
+	assert.Equal(t, PrereleaseOnly, only.Normalize().Prereleases())
+	assert.True(t, include.Normalize().Check(mustVersion(t, "go1.22rc1")))
+}
+
+func TestConstraints_prereleaseFallback(t *testing.T) {
+	c, err := NewConstraintsWithOptions("1.21.x", &ConstraintsOptions{Prereleases: PrereleaseFallback})
+	require.NoError(t, err)
+	for _, s := range []string{"go1.21.5", "go1.21rc2", "go1.22rc1", "go1.20.1"} {
+		v := mustVersion(t, s)
+		want := len(c.FilterVersions([]*Version{v})) == 1
+		assert.Equal(t, want, c.Check(v), s)
+		assert.Equal(t, want, c.Explain(v).Matched, s)
+	}
+	assert.True(t, c.Check(mustVersion(t, "go1.21rc2")))
+	assert.True(t, c.Satisfiable())
+	assert.Empty(t, c.Normalized().Prereleases)
+}
+
+func TestConstraints_Explain_prereleases(t *testing.T) {
+	c, err := NewConstraintsWithOptions(">=1.22rc1", &ConstraintsOptions{Prereleases: PrereleaseExclude})
+	require.NoError(t, err)
+	got := c.Explain(mustVersion(t, "go1.22rc2"))
+	require.False(t, got.Matched)
+	require.Equal(t, ComparatorExplanation{
+		Comparator:         ">=go1.22rc1",
+		PrereleaseExcluded: true,
+		Reason:             "go1.22rc2 is a prerelease and prereleases are excluded",
+	}, got.Branches[0].Comparators[0])
+
+	c, err = NewConstraintsWithOptions("1.22.x", &ConstraintsOptions{Prereleases: PrereleaseOnly})
+	require.NoError(t, err)
+	got = c.Explain(mustVersion(t, "go1.22.0"))
+	require.False(t, got.Matched)
+	require.Equal(t, "go1.22.0 is not a prerelease and only prereleases are matched", got.Branches[0].Comparators[0].Reason)
+	require.True(t, c.Explain(mustVersion(t, "go1.22rc1")).Matched)
+}
+
+func TestVersion_PrereleaseKind(t *testing.T) {
+	for _, td := range []struct {
+		version string
+		kind    PrereleaseKind
+		number  uint64
+	}{
+		{version: "go1.21.0", kind: PrereleaseKindRelease},
+		{version: "go1.21rc2", kind: PrereleaseKindRC, number: 2},
+		{version: "go1.21rc10", kind: PrereleaseKindRC, number: 10},
+		{version: "go1.9beta1", kind: PrereleaseKindBeta, number: 1},
+		{version: "go1.21alpha3", kind: PrereleaseKindOther, number: 3},
+		{version: "go1.21rc", kind: PrereleaseKindRC},
+		{version: "go1.21rc1a", kind: PrereleaseKindOther},
+		{version: "devel go1.23", kind: PrereleaseKindDevel},
+	} {
+		t.Run(td.version, func(t *testing.T) {
+			v := mustVersion(t, td.version)
+			assert.Equal(t, td.kind, v.PrereleaseKind())
+			assert.Equal(t, td.number, v.PrereleaseNumber())
+		})
+	}
+	assert.Equal(t, "rc", PrereleaseKindRC.String())
+	assert.Equal(t, "PrereleaseKind(9)", PrereleaseKind(9).String())
+}
+
+func TestConstraints_Check_numericPrereleases(t *testing.T) {
+	c, err := NewConstraints(">=1.22rc2")
+	require.NoError(t, err)
+	assert.True(t, c.Check(mustVersion(t, "go1.22rc10")))
+	assert.False(t, c.Check(mustVersion(t, "go1.22beta9")))
+}
@@ -0,0 +1,183 @@
+package goversion
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// ErrInvalidSemver is returned when a semver version or constraint is not
+// valid or can't be written in go syntax
+var ErrInvalidSemver = fmt.Errorf("invalid semver")
+
+// ErrNoSemver is returned when a go version has no semver equivalent
+var ErrNoSemver = fmt.Errorf("no semver equivalent")
+
+// semverConstraintVersionRegexp matches the versions in a semver constraint
+// along with the operators or separators before them.
+var semverConstraintVersionRegexp *regexp.Regexp
+
+var initSemverRegexpOnce sync.Once
+
+func initSemverRegexp() {
+	initSemverRegexpOnce.Do(func() {
+		semverConstraintVersionRegexp = regexp.MustCompile(`(\A|[\s|,<>=~^])v?(x|X|\*|\d+)(\.(?:x|X|\*|\d+))?(\.(?:x|X|\*|\d+))?(?:-([0-9A-Za-z.-]+))?(?:\+([0-9A-Za-z.-]+))?`)
+	})
+}
+
+// SemverString returns v as a semver version like "1.21.0" or "1.21.0-rc1".
+// A missing minor or patch is written as 0 and the prerelease follows a
+// hyphen. Devel builds have no semver equivalent and return an empty string.
+func (v *Version) SemverString() string {
+	if v.devel {
+		return ""
+	}
+	return semverString(v.major, v.minor, v.patch, v.prerelease)
+}
+
+// GoToSemver converts a go version like "go1.21rc1" to semver like
+// "1.21.0-rc1". See Version.SemverString for the rules. The error is a
+// *ParseError when version isn't valid and matches ErrNoSemver for devel
+// builds.
+//
+// Semver can't tell a language version like go1.21 from the release go1.21.0,
+// so both convert to "1.21.0". Every go release and prerelease name survives a
+// round trip through GoToSemver and SemverToGo.
+func GoToSemver(version string) (string, error) {
+	v, err := NewVersion(version)
+	if err != nil {
+		return "", err
+	}
+	if v.devel {
+		return "", fmt.Errorf("%w for devel build %q", ErrNoSemver, version)
+	}
+	return v.SemverString(), nil
+}
+
+// SemverToGo converts a semver version like "1.21.0-rc1" or "v1.20.0" to the
+// name of the go release it refers to like "go1.21rc1" or "go1.20". Releases
+// with patch 0 are named the way Lang.FirstRelease names them, and a
+// prerelease of a .0 release follows the language version like go1.21rc1. The
+// prerelease must be letters and digits only and start with a letter like
+// every go prerelease, so "1.21.3-2" is invalid. Build metadata isn't
+// allowed because go versions have neither dots in prereleases nor build
+// metadata. The error matches ErrInvalidSemver.
+func SemverToGo(version string) (string, error) {
+	v, ok := parseSemver(version)
+	if !ok {
+		return "", fmt.Errorf("%w version %q", ErrInvalidSemver, version)
+	}
+	return v.String(), nil
+}
+
+// parseSemver parses a full semver version with an optional "v" prefix into
+// the go version it refers to.
+func parseSemver(version string) (*Version, bool) {
+	s := strings.TrimPrefix(version, "v")
+	var parts [3]uint64
+	for i := range parts {
+		if i > 0 {
+			if s == "" || s[0] != '.' {
+				return nil, false
+			}
+			s = s[1:]
+		}
+		var ok bool
+		parts[i], s, ok = parseVersionNumber(s)
+		if !ok {
+			return nil, false
+		}
+	}
+	var prerelease string
+	if s != "" {
+		if s[0] != '-' || len(s) == 1 {
+			return nil, false
+		}
+		prerelease = s[1:]
+		if !isGoPrerelease(prerelease) {
+			return nil, false
+		}
+	}
+	return semverRelease(parts[0], parts[1], parts[2], prerelease), true
+}
+
+// isGoPrerelease returns true if the semver prerelease pre can be the
+// prerelease of a go version. Go prereleases like beta1 or rc2 are letters and
+// digits that start with a letter. Appending one that starts with a digit to
+// the version number would give a different version like go1.21.32 for
+// 1.21.3-2.
+func isGoPrerelease(pre string) bool {
+	if pre == "" || !(pre[0] >= 'a' && pre[0] <= 'z' || pre[0] >= 'A' && pre[0] <= 'Z') {
+		return false
+	}
+	for i := 1; i < len(pre); i++ {
+		if !isAlnum(pre[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// semverRelease returns the go release or prerelease that the semver version
+// major.minor.patch-prerelease refers to.
+func semverRelease(major, minor, patch uint64, prerelease string) *Version {
+	lang := Lang{major: major, minor: minor}
+	switch {
+	case patch != 0:
+		return buildVersion(major, minor, patch, prerelease, false, false)
+	case prerelease == "":
+		return lang.FirstRelease()
+	default:
+		return buildVersion(major, minor, 0, prerelease, minor == 0, true)
+	}
+}
+
+// GoConstraintsToSemver converts constraints in go syntax like ">= 1.21rc1" to
+// semver syntax like ">= 1.21.0-rc1". Versions are converted like GoToSemver
+// and everything else is kept as written. The error is a *ParseError.
+func GoConstraintsToSemver(c string) (string, error) {
+	_, err := NewConstraints(c)
+	if err != nil {
+		return "", err
+	}
+	return go2SemverRange(c), nil
+}
+
+// SemverConstraintsToGo converts semver constraints like ">= 1.21.0-rc1" to go
+// syntax like ">= 1.21rc1". Versions are converted like SemverToGo except that
+// partial versions and wildcards like "1.21" or "1.21.x" are kept as written
+// and a patch of 0 is only dropped for prereleases. Converting the result back
+// with GoConstraintsToSemver gives c without "v" prefixes when c only has full
+// versions. The error matches ErrInvalidSemver.
+func SemverConstraintsToGo(c string) (string, error) {
+	initSemverRegexp()
+	var sb strings.Builder
+	last := 0
+	for _, sm := range semverConstraintVersionRegexp.FindAllStringSubmatchIndex(c, -1) {
+		group := func(i int) string {
+			if sm[2*i] < 0 {
+				return ""
+			}
+			return c[sm[2*i]:sm[2*i+1]]
+		}
+		sb.WriteString(c[last:sm[0]])
+		last = sm[1]
+		prerelease, build := group(5), group(6)
+		if build != "" || strings.ContainsAny(prerelease, ".-") || prerelease != "" && !isGoPrerelease(prerelease) {
+			return "", fmt.Errorf("%w constraint %q: %q can't be written as a go version", ErrInvalidSemver, c, c[sm[3]:sm[1]])
+		}
+		patch := group(4)
+		if prerelease != "" && patch == ".0" {
+			patch = ""
+		}
+		sb.WriteString(group(1) + group(2) + group(3) + patch + prerelease)
+	}
+	sb.WriteString(c[last:])
+	goRange := sb.String()
+	_, err := NewConstraints(goRange)
+	if err != nil {
+		return "", fmt.Errorf("%w constraint %q", ErrInvalidSemver, c)
+	}
+	return goRange, nil
+}
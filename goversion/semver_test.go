@@ -0,0 +1,141 @@
+package goversion
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGoToSemver(t *testing.T) {
+	for _, td := range []struct {
+		version string
+		want    string
+	}{
+		{version: "go1", want: "1.0.0"},
+		{version: "go1.0.3", want: "1.0.3"},
+		{version: "go1.9beta2", want: "1.9.0-beta2"},
+		{version: "go1.20", want: "1.20.0"},
+		{version: "go1.21", want: "1.21.0"},
+		{version: "go1.21rc1", want: "1.21.0-rc1"},
+		{version: "go1.21.0", want: "1.21.0"},
+		{version: "1.21.3", want: "1.21.3"},
+	} {
+		t.Run(td.version, func(t *testing.T) {
+			got, err := GoToSemver(td.version)
+			require.NoError(t, err)
+			assert.Equal(t, td.want, got)
+			assert.Equal(t, td.want, mustVersion(t, td.version).SemverString())
+		})
+	}
+
+	_, err := GoToSemver("go1.21-rc1")
+	var parseErr *ParseError
+	require.True(t, errors.As(err, &parseErr))
+
+	_, err = GoToSemver("devel go1.23")
+	require.True(t, errors.Is(err, ErrNoSemver))
+	assert.Equal(t, "", mustVersion(t, "devel go1.23").SemverString())
+}
+
+func TestSemverToGo(t *testing.T) {
+	for _, td := range []struct {
+		version string
+		want    string
+	}{
+		{version: "1.0.0", want: "go1"},
+		{version: "1.0.3", want: "go1.0.3"},
+		{version: "1.9.0-beta2", want: "go1.9beta2"},
+		{version: "1.20.0", want: "go1.20"},
+		{version: "v1.20.14", want: "go1.20.14"},
+		{version: "1.21.0-rc1", want: "go1.21rc1"},
+		{version: "1.21.0", want: "go1.21.0"},
+		{version: "1.21.1-rc1", want: "go1.21.1rc1"},
+		{version: "2.0.0", want: "go2"},
+	} {
+		t.Run(td.version, func(t *testing.T) {
+			got, err := SemverToGo(td.version)
+			require.NoError(t, err)
+			assert.Equal(t, td.want, got)
+		})
+	}
+
+	for _, version := range []string{
+		"", "1.21", "1.21.0-", "1.21.0-rc.1", "1.21.0+build", "01.21.0", "go1.21.0", "1.21.0 ",
+		"1.21.0-1", "1.21.3-2", "1.0.0-5",
+	} {
+		t.Run(version, func(t *testing.T) {
+			_, err := SemverToGo(version)
+			require.True(t, errors.Is(err, ErrInvalidSemver))
+		})
+	}
+}
+
+func TestSemver_roundTrip(t *testing.T) {
+	for _, version := range []string{
+		"go1", "go1.0.1", "go1.2rc2", "go1.9beta2", "go1.9.7", "go1.16", "go1.20",
+		"go1.20.14", "go1.21rc4", "go1.21.0", "go1.21.1", "go1.22rc1", "go1.22.0",
+	} {
+		t.Run(version, func(t *testing.T) {
+			sv, err := GoToSemver(version)
+			require.NoError(t, err)
+			got, err := SemverToGo(sv)
+			require.NoError(t, err)
+			assert.Equal(t, version, got)
+		})
+	}
+}
+
+func TestGoConstraintsToSemver(t *testing.T) {
+	got, err := GoConstraintsToSemver(">= 1.21rc1, < 1.22 || 1.19.x")
+	require.NoError(t, err)
+	assert.Equal(t, ">= 1.21.0-rc1, < 1.22.0 || 1.19.x", got)
+
+	_, err = GoConstraintsToSemver(">= foo")
+	require.True(t, errors.Is(err, ErrInvalidConstraint))
+}
+
+func TestSemverConstraintsToGo(t *testing.T) {
+	for _, td := range []struct {
+		constraint string
+		want       string
+	}{
+		{constraint: ">= 1.21.0-rc1, < 1.22.0", want: ">= 1.21rc1, < 1.22.0"},
+		{constraint: ">=v1.20.0 <1.21.1-rc1", want: ">=1.20.0 <1.21.1rc1"},
+		{constraint: "~1.21 || 1.19.x || *", want: "~1.21 || 1.19.x || *"},
+		{constraint: "1.18.0 - 1.20.0", want: "1.18.0 - 1.20.0"},
+	} {
+		t.Run(td.constraint, func(t *testing.T) {
+			got, err := SemverConstraintsToGo(td.constraint)
+			require.NoError(t, err)
+			assert.Equal(t, td.want, got)
+		})
+	}
+
+	for _, c := range []string{
+		">= 1.21.0-rc.1", "1.21.0+build", ">= foo", ">=1.21.0-1", "<1.20.3-4", ">= 1.20.0, < 1.21.3-2",
+	} {
+		t.Run(c, func(t *testing.T) {
+			_, err := SemverConstraintsToGo(c)
+			require.True(t, errors.Is(err, ErrInvalidSemver))
+		})
+	}
+}
+
+func TestSemverConstraints_roundTrip(t *testing.T) {
+	for _, c := range []string{
+		">= 1.21.0-rc1, < 1.22.0",
+		">=1.20.0 <1.21.1-rc1 || 1.19.3",
+		"!= 1.21.0-rc2",
+		"~1.21.0 || ^1.19.0-beta1",
+	} {
+		t.Run(c, func(t *testing.T) {
+			goRange, err := SemverConstraintsToGo(c)
+			require.NoError(t, err)
+			got, err := GoConstraintsToSemver(goRange)
+			require.NoError(t, err)
+			assert.Equal(t, c, got)
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package goversion
+
+import (
+	"slices"
+	"strings"
+)
+
+// Set is a sorted set of versions without duplicates. Versions are duplicates
+// when they are identical, so go1.21 and go1.21.0 are both kept even though
+// they are Equal. The zero value is an empty set.
+type Set struct {
+	versions []*Version
+}
+
+// NewSet returns a Set holding versions. Nil versions are ignored.
+func NewSet(versions ...*Version) *Set {
+	sorted := make([]*Version, 0, len(versions))
+	for _, v := range versions {
+		if v != nil {
+			sorted = append(sorted, v)
+		}
+	}
+	slices.SortStableFunc(sorted, compareIdentity)
+	return &Set{
+		versions: slices.CompactFunc(sorted, identical),
+	}
+}
+
+// Len returns the number of versions in s.
+func (s *Set) Len() int {
+	return len(s.versions)
+}
+
+// Contains tests if s contains a version identical to v.
+func (s *Set) Contains(v *Version) bool {
+	if v == nil {
+		return false
+	}
+	_, found := slices.BinarySearchFunc(s.versions, v, compareIdentity)
+	return found
+}
+
+// Ascending returns the versions in s from oldest to newest.
+func (s *Set) Ascending() Collection {
+	return slices.Clone(Collection(s.versions))
+}
+
+// Descending returns the versions in s from newest to oldest.
+func (s *Set) Descending() Collection {
+	result := s.Ascending()
+	slices.Reverse(result)
+	return result
+}
+
+// Union returns a Set of the versions in either s or o.
+func (s *Set) Union(o *Set) *Set {
+	return s.merge(o, true, true, true)
+}
+
+// Intersect returns a Set of the versions in both s and o.
+func (s *Set) Intersect(o *Set) *Set {
+	return s.merge(o, false, true, false)
+}
+
+// Difference returns a Set of the versions in s that are not in o.
+func (s *Set) Difference(o *Set) *Set {
+	return s.merge(o, true, false, false)
+}
+
+// merge walks s and o in order and keeps versions that are only in s, in both
+// or only in o depending on the flags.
+func (s *Set) merge(o *Set, onlyS, both, onlyO bool) *Set {
+	a, b := s.versions, o.versions
+	result := make([]*Version, 0, len(a)+len(b))
+	for len(a) > 0 && len(b) > 0 {
+		switch c := compareIdentity(a[0], b[0]); {
+		case c < 0:
+			if onlyS {
+				result = append(result, a[0])
+			}
+			a = a[1:]
+		case c > 0:
+			if onlyO {
+				result = append(result, b[0])
+			}
+			b = b[1:]
+		default:
+			if both {
+				result = append(result, a[0])
+			}
+			a, b = a[1:], b[1:]
+		}
+	}
+	if onlyS {
+		result = append(result, a...)
+	}
+	if onlyO {
+		result = append(result, b...)
+	}
+	return &Set{versions: result}
+}
+
+// compareIdentity orders versions by SemverOrdering and breaks ties between
+// versions that are Equal but not identical, like go1.21 and go1.21.0, so that
+// only identical versions compare as 0.
+func compareIdentity(a, b *Version) int {
+	if c := a.Compare(b); c != 0 {
+		return c
+	}
+	if c := GoverOrdering.Compare(a, b); c != 0 {
+		return c
+	}
+	if identical(a, b) {
+		return 0
+	}
+	return strings.Compare(a.String(), b.String())
+}
@@ -0,0 +1,64 @@
+package goversion
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func mustSet(t *testing.T, versions ...string) *Set {
+	t.Helper()
+	return NewSet(mustCollection(t, versions...)...)
+}
+
+func TestNewSet(t *testing.T) {
+	s := mustSet(t, "go1.21.0", "go1.20", "go1.21", "go1.21rc1", "go1.20", "go1.21.0", "devel go1.21")
+	assert.Equal(t, 5, s.Len())
+	assert.Equal(t, []string{"go1.20", "devel go1.21", "go1.21rc1", "go1.21", "go1.21.0"}, collectionStrings(s.Ascending()))
+	assert.Equal(t, []string{"go1.21.0", "go1.21", "go1.21rc1", "devel go1.21", "go1.20"}, collectionStrings(s.Descending()))
+	assert.True(t, s.Contains(mustVersion(t, "go1.21")))
+	assert.True(t, s.Contains(mustVersion(t, "go1.21.0")))
+	assert.False(t, s.Contains(mustVersion(t, "go1.20.0")))
+	assert.False(t, s.Contains(nil))
+
+	assert.Equal(t, 1, NewSet(nil, mustVersion(t, "go1.20")).Len())
+
+	var empty Set
+	assert.Equal(t, 0, empty.Len())
+	assert.Empty(t, empty.Ascending())
+	assert.False(t, empty.Contains(mustVersion(t, "go1.20")))
+}
+
+func TestSet_Ascending_copies(t *testing.T) {
+	s := mustSet(t, "go1.20", "go1.21.0")
+	asc := s.Ascending()
+	asc[0] = mustVersion(t, "go1.22.0")
+	assert.Equal(t, []string{"go1.20", "go1.21.0"}, collectionStrings(s.Ascending()))
+}
+
+func TestSet_operations(t *testing.T) {
+	published := mustSet(t, "go1.20", "go1.20.1", "go1.21rc1", "go1.21.0", "go1.21.1")
+	mirrored := mustSet(t, "go1.20", "go1.21", "go1.21.0", "go1.21.1", "go1.22rc1")
+
+	assert.Equal(t,
+		[]string{"go1.20", "go1.20.1", "go1.21rc1", "go1.21", "go1.21.0", "go1.21.1", "go1.22rc1"},
+		collectionStrings(published.Union(mirrored).Ascending()),
+	)
+	assert.Equal(t,
+		[]string{"go1.20", "go1.21.0", "go1.21.1"},
+		collectionStrings(published.Intersect(mirrored).Ascending()),
+	)
+	assert.Equal(t,
+		[]string{"go1.20.1", "go1.21rc1"},
+		collectionStrings(published.Difference(mirrored).Ascending()),
+	)
+	assert.Equal(t,
+		[]string{"go1.21", "go1.22rc1"},
+		collectionStrings(mirrored.Difference(published).Ascending()),
+	)
+
+	var empty Set
+	assert.Equal(t, published.Ascending(), published.Union(&empty).Ascending())
+	assert.Equal(t, 0, published.Intersect(&empty).Len())
+	assert.Equal(t, published.Ascending(), published.Difference(&empty).Ascending())
+}
@@ -0,0 +1,87 @@
+package goversion
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ToolchainModulePath is the path of the module the go command downloads
+// toolchains from.
+const ToolchainModulePath = "golang.org/toolchain"
+
+// toolchainModulePrefix is the packaging version the go command puts before
+// the toolchain name in toolchain module versions.
+const toolchainModulePrefix = "v0.0.1-"
+
+// ErrInvalidToolchainModule is returned when a toolchain module version is not valid
+var ErrInvalidToolchainModule = fmt.Errorf("invalid toolchain module version")
+
+// ToolchainModule is a version of the golang.org/toolchain module like
+// "v0.0.1-go1.21.0.linux-amd64". The go command downloads these when it
+// switches toolchains.
+type ToolchainModule struct {
+	// Name is the name of the toolchain like "go1.21.0".
+	Name string
+
+	// Version is the version of the toolchain.
+	Version *Version
+
+	// GOOS and GOARCH are the platform the toolchain runs on. GOARCH is the
+	// go architecture name, so 32-bit arm is "arm" even though go.dev/dl names
+	// those files "armv6l".
+	GOOS   string
+	GOARCH string
+}
+
+// NewToolchainModule returns the toolchain module for running toolchain v on
+// goos and goarch.
+func NewToolchainModule(v *Version, goos, goarch string) *ToolchainModule {
+	return &ToolchainModule{
+		Name:    v.String(),
+		Version: v,
+		GOOS:    goos,
+		GOARCH:  goarch,
+	}
+}
+
+// ParseToolchainModule parses a toolchain module version like
+// "v0.0.1-go1.21.0.linux-amd64". The module path may be included like
+// "golang.org/toolchain@v0.0.1-go1.21.0.linux-amd64". The error matches
+// ErrInvalidToolchainModule.
+func ParseToolchainModule(s string) (*ToolchainModule, error) {
+	version := strings.TrimPrefix(s, ToolchainModulePath+"@")
+	if !strings.HasPrefix(version, toolchainModulePrefix) {
+		return nil, fmt.Errorf("%w %q: must start with %s", ErrInvalidToolchainModule, s, toolchainModulePrefix)
+	}
+	version = strings.TrimPrefix(version, toolchainModulePrefix)
+	i := strings.LastIndexByte(version, '.')
+	if i == -1 {
+		return nil, fmt.Errorf("%w %q: missing platform", ErrInvalidToolchainModule, s)
+	}
+	name, platform := version[:i], version[i+1:]
+	goos, goarch, ok := strings.Cut(platform, "-")
+	if !ok || goos == "" || goarch == "" {
+		return nil, fmt.Errorf("%w %q: invalid platform %q", ErrInvalidToolchainModule, s, platform)
+	}
+	v, ok := toolchainVersion(name)
+	if !ok {
+		return nil, fmt.Errorf("%w %q: invalid toolchain %q", ErrInvalidToolchainModule, s, name)
+	}
+	return &ToolchainModule{
+		Name:    name,
+		Version: v,
+		GOOS:    goos,
+		GOARCH:  goarch,
+	}, nil
+}
+
+// ModuleVersion returns the module version like "v0.0.1-go1.21.0.linux-amd64".
+func (m *ToolchainModule) ModuleVersion() string {
+	return toolchainModulePrefix + m.Name + "." + m.GOOS + "-" + m.GOARCH
+}
+
+// String returns the module path and version like
+// "golang.org/toolchain@v0.0.1-go1.21.0.linux-amd64".
+func (m *ToolchainModule) String() string {
+	return ToolchainModulePath + "@" + m.ModuleVersion()
+}
@@ -0,0 +1,55 @@
+package goversion
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseToolchainModule(t *testing.T) {
+	for _, td := range []struct {
+		input  string
+		name   string
+		goos   string
+		goarch string
+	}{
+		{input: "v0.0.1-go1.21.0.linux-amd64", name: "go1.21.0", goos: "linux", goarch: "amd64"},
+		{input: "golang.org/toolchain@v0.0.1-go1.21rc2.darwin-arm64", name: "go1.21rc2", goos: "darwin", goarch: "arm64"},
+		{input: "v0.0.1-go1.20.linux-arm", name: "go1.20", goos: "linux", goarch: "arm"},
+		{input: "v0.0.1-go1.21.3-bigcorp.windows-amd64", name: "go1.21.3-bigcorp", goos: "windows", goarch: "amd64"},
+	} {
+		t.Run(td.input, func(t *testing.T) {
+			m, err := ParseToolchainModule(td.input)
+			require.NoError(t, err)
+			assert.Equal(t, td.name, m.Name)
+			assert.Equal(t, td.goos, m.GOOS)
+			assert.Equal(t, td.goarch, m.GOARCH)
+			assert.Equal(t, "golang.org/toolchain@"+m.ModuleVersion(), m.String())
+			got, err := ParseToolchainModule(m.ModuleVersion())
+			require.NoError(t, err)
+			assert.Equal(t, m, got)
+		})
+	}
+	m, err := ParseToolchainModule("v0.0.1-go1.21.3-bigcorp.windows-amd64")
+	require.NoError(t, err)
+	assert.Equal(t, "go1.21.3", m.Version.String())
+
+	for _, input := range []string{
+		"", "go1.21.0.linux-amd64", "v0.0.2-go1.21.0.linux-amd64", "v0.0.1-go1.21.0",
+		"v0.0.1-go1.21.0.linux", "v0.0.1-go1.21.0.-amd64", "v0.0.1-1.21.0.linux-amd64",
+		"v0.0.1-gox.linux-amd64",
+	} {
+		t.Run(input, func(t *testing.T) {
+			_, err := ParseToolchainModule(input)
+			require.True(t, errors.Is(err, ErrInvalidToolchainModule))
+		})
+	}
+}
+
+func TestNewToolchainModule(t *testing.T) {
+	m := NewToolchainModule(mustVersion(t, "go1.22.1"), "linux", "amd64")
+	assert.Equal(t, "v0.0.1-go1.22.1.linux-amd64", m.ModuleVersion())
+	assert.Equal(t, "golang.org/toolchain@v0.0.1-go1.22.1.linux-amd64", m.String())
+}